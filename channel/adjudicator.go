@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/stellar/go/xdr"
@@ -37,29 +38,32 @@ var DefaultChallengeDuration = time.Duration(20) * time.Second //nolint:gomnd
 
 // Adjudicator implements the Adjudicator interface for Stellar.
 type Adjudicator struct {
-	challengeDuration *time.Duration
-	log               log.Embedding
-	CB                *client.ContractBackend
-	acc               *wallet.Account
-	assetAddrs        []xdr.ScVal // xdr.ScAddress
-	perunAddr         xdr.ScAddress
-	maxIters          int
-	pollingInterval   time.Duration
-	oneWithdrawer     bool
+	log             log.Embedding
+	CB              client.Invoker
+	acc             *wallet.Account
+	assetAddrs      []xdr.ScVal // xdr.ScAddress
+	perunAddr       xdr.ScAddress
+	maxIters        int
+	pollingInterval time.Duration
+	subPollInterval time.Duration
+	oneWithdrawer   bool
+	ttlKeeper       *client.TTLKeeper
+	hubMu           sync.Mutex
+	hub             *EventHub
 }
 
 // NewAdjudicator returns a new Adjudicator.
-func NewAdjudicator(acc *wallet.Account, cb *client.ContractBackend, perunID xdr.ScAddress, assetIDs []xdr.ScVal, oneWithdrawer bool) *Adjudicator {
+func NewAdjudicator(acc *wallet.Account, cb client.Invoker, perunID xdr.ScAddress, assetIDs []xdr.ScVal, oneWithdrawer bool) *Adjudicator {
 	return &Adjudicator{
-		challengeDuration: &DefaultChallengeDuration,
-		CB:                cb,
-		acc:               acc,
-		perunAddr:         perunID,
-		assetAddrs:        assetIDs,
-		maxIters:          MaxIterationsUntilAbort,
-		pollingInterval:   DefaultPollingInterval,
-		log:               log.MakeEmbedding(log.Default()),
-		oneWithdrawer:     oneWithdrawer,
+		CB:              cb,
+		acc:             acc,
+		perunAddr:       perunID,
+		assetAddrs:      assetIDs,
+		maxIters:        MaxIterationsUntilAbort,
+		pollingInterval: DefaultPollingInterval,
+		subPollInterval: DefaultSubscriptionPollingInterval,
+		log:             log.MakeEmbedding(log.Default()),
+		oneWithdrawer:   oneWithdrawer,
 	}
 }
 
@@ -73,15 +77,60 @@ func (a *Adjudicator) GetAssetAddrs() []xdr.ScVal {
 	return a.assetAddrs
 }
 
-// Subscribe subscribes to the adjudicator.
+// Subscribe subscribes to the adjudicator. All subscriptions of the
+// adjudicator are served by its EventHub.
 func (a *Adjudicator) Subscribe(ctx context.Context, cid pchannel.ID) (pchannel.AdjudicatorSubscription, error) {
-	perunAddr := a.GetPerunAddr()
-	assetAddrs := a.GetAssetAddrs()
-	return NewAdjudicatorSub(ctx, cid, a.CB, perunAddr, assetAddrs, a.challengeDuration)
+	sub, err := a.EventHub().Subscribe(ctx, cid)
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// EventHub returns the EventHub serving the subscriptions of the adjudicator.
+// It is created on first use, unless it was set with SetEventHub.
+func (a *Adjudicator) EventHub() *EventHub {
+	a.hubMu.Lock()
+	defer a.hubMu.Unlock()
+	if a.hub == nil {
+		a.hub = NewEventHub(a.CB, a.perunAddr, a.subPollInterval)
+	}
+	return a.hub
+}
+
+// SetEventHub sets the EventHub serving the subscriptions of the adjudicator,
+// e.g., to share one hub between the adjudicators of all clients of a
+// contract.
+func (a *Adjudicator) SetEventHub(h *EventHub) {
+	a.hubMu.Lock()
+	defer a.hubMu.Unlock()
+	a.hub = h
+}
+
+// SetSubscriptionPollingInterval sets the interval in which subscriptions
+// created by Subscribe poll the channel. It has no effect after the EventHub
+// of the adjudicator was created.
+func (a *Adjudicator) SetSubscriptionPollingInterval(d time.Duration) {
+	a.subPollInterval = d
+}
+
+// SetTTLKeeper sets the TTLKeeper from which withdrawn channels are removed.
+func (a *Adjudicator) SetTTLKeeper(k *client.TTLKeeper) {
+	a.ttlKeeper = k
 }
 
 // Withdraw withdraws the channel.
 func (a *Adjudicator) Withdraw(ctx context.Context, req pchannel.AdjudicatorReq, smap pchannel.StateMap) error {
+	if err := a.withdrawChannel(ctx, req); err != nil {
+		return err
+	}
+	if a.ttlKeeper != nil {
+		a.ttlKeeper.Untrack(req.Tx.State.ID)
+	}
+	return nil
+}
+
+func (a *Adjudicator) withdrawChannel(ctx context.Context, req pchannel.AdjudicatorReq) error {
 	log.Println("Withdraw called by Adjudicator")
 	chanControl, errChanState := a.CB.GetChannelInfo(ctx, a.perunAddr, req.Tx.State.ID)
 	if errChanState != nil {
@@ -122,13 +171,16 @@ func (a *Adjudicator) Withdraw(ctx context.Context, req pchannel.AdjudicatorReq,
 			log.Println("Error closing channel: ", err)
 			return err
 		}
-		log.Println("closed channel, ", err)
-		return err
+		log.Println("Closed channel")
+		if a.oneWithdrawer && req.Idx == 0 {
+			return nil
+		}
+		return a.handleWithdrawal(ctx, req)
 	}
 
 	if err := a.ForceClose(ctx, req.Tx.State, req.Tx.Sigs); err != nil {
 		log.Println("ForceClose called")
-		if errors.Is(err, ErrChannelAlreadyClosed) {
+		if errors.Is(err, ErrChannelAlreadyClosed) || errors.Is(err, client.ErrForceCloseOnClosedChannel) {
 			return a.handleWithdrawal(ctx, req)
 		}
 		return err
@@ -200,7 +252,7 @@ func (a *Adjudicator) ForceClose(ctx context.Context, state *pchannel.State, sig
 }
 
 // Progress is not relevant for Stellar channels.
-func (a Adjudicator) Progress(ctx context.Context, req pchannel.ProgressReq) error {
+func (a *Adjudicator) Progress(ctx context.Context, req pchannel.ProgressReq) error {
 	// only relevant for AppChannels
 	return nil
 }
@@ -17,114 +17,155 @@ package channel
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
-	"github.com/stellar/go/xdr"
 	pchannel "perun.network/go-perun/channel"
 	log "perun.network/go-perun/log"
 	pkgsync "polycry.pt/poly-go/sync"
 
-	"perun.network/perun-stellar-backend/client"
 	"perun.network/perun-stellar-backend/event"
 	"perun.network/perun-stellar-backend/wire"
 )
 
 const (
+	// Deprecated: Subscriptions queue their events without limit, see
+	// EventHub.
 	DefaultBufferSize                  = 1024
 	DefaultSubscriptionPollingInterval = time.Duration(15) * time.Second
 )
 
 // AdjEventSub holds the necessary information for an Adjudicator Subscription.
+// Its events are read and dispatched by an EventHub, see EventHub for how the
+// events are obtained.
 type AdjEventSub struct {
-	challengeDuration *time.Duration
-	cb                *client.ContractBackend
-	chanControl       wire.Control
-	cid               pchannel.ID
-	perunAddr         xdr.ScAddress
-	assetAddrs        []xdr.ScVal
-	events            chan event.PerunEvent
-	subErrors         chan error
-	err               error
-	cancel            context.CancelFunc
-	closer            *pkgsync.Closer
-	pollInterval      time.Duration
-	log               log.Embedding
+	hub         *EventHub
+	chanControl wire.Control
+	cid         pchannel.ID
+	closer      *pkgsync.Closer
+	log         log.Embedding
+	// repoll is set if the channel must be polled in the next iteration of
+	// the EventHub, because polling it failed.
+	repoll bool
+
+	// notify signals that events were queued or the subscription finished.
+	notify chan struct{}
+
+	mu       sync.Mutex
+	queue    []event.PerunEvent
+	finished bool
+	err      error
+	stopCtx  func() bool
 }
 
-// NewAdjudicatorSub creates a new Adjudicator Subscription.
-func NewAdjudicatorSub(ctx context.Context, cid pchannel.ID, cb *client.ContractBackend, perunAddr xdr.ScAddress, assetAddrs []xdr.ScVal, challengeDuration *time.Duration) (pchannel.AdjudicatorSubscription, error) {
-	sub := &AdjEventSub{
-		challengeDuration: challengeDuration,
-		cb:                cb,
-		chanControl:       wire.Control{},
-		cid:               cid,
-		perunAddr:         perunAddr,
-		assetAddrs:        assetAddrs,
-		events:            make(chan event.PerunEvent, DefaultBufferSize),
-		subErrors:         make(chan error, 1),
-		pollInterval:      DefaultSubscriptionPollingInterval,
-		closer:            new(pkgsync.Closer),
-		log:               log.MakeEmbedding(log.Default()),
-	}
-
-	ctx, sub.cancel = context.WithCancel(ctx)
-	go sub.run(ctx)
+// NewAdjudicatorSub creates a new Adjudicator Subscription served by the
+// given EventHub. All subscriptions to channels of the same contract should
+// share one hub, so that its events are read by a single loop. The timeouts of
+// the events are derived from the channel on-chain.
+func NewAdjudicatorSub(ctx context.Context, hub *EventHub, cid pchannel.ID) (pchannel.AdjudicatorSubscription, error) {
+	sub, err := hub.Subscribe(ctx, cid)
+	if err != nil {
+		return nil, err
+	}
 	return sub, nil
 }
 
-func (s *AdjEventSub) run(ctx context.Context) {
-	s.log.Log().Info("Listening for channel state changes")
-	chanControl, err := s.cb.GetChannelInfo(ctx, s.perunAddr, s.cid)
+func newAdjEventSub(hub *EventHub, cid pchannel.ID, control wire.Control) *AdjEventSub {
+	return &AdjEventSub{
+		hub:         hub,
+		chanControl: control,
+		cid:         cid,
+		notify:      make(chan struct{}, 1),
+		closer:      new(pkgsync.Closer),
+		log:         log.MakeEmbedding(log.Default()),
+	}
+}
+
+// push queues the events of the subscription. It is called by the EventHub
+// and never blocks.
+func (s *AdjEventSub) push(evs ...event.PerunEvent) {
+	s.mu.Lock()
+	s.queue = append(s.queue, evs...)
+	s.mu.Unlock()
+	s.signal()
+}
+
+// finish ends the subscription with the error, after its queued events were
+// read. It is called by the EventHub.
+func (s *AdjEventSub) finish(err error) {
+	s.hub.remove(s)
+	s.mu.Lock()
+	s.err, s.finished = err, true
+	s.mu.Unlock()
+	s.signal()
+}
+
+// pop removes the next queued event. It reports false if no event is queued
+// and whether the subscription finished.
+func (s *AdjEventSub) pop() (ev event.PerunEvent, ok, finished bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queue) == 0 {
+		return nil, false, s.finished
+	}
+	ev, s.queue[0] = s.queue[0], nil
+	s.queue = s.queue[1:]
+	return ev, true, false
+}
+
+func (s *AdjEventSub) signal() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// setStop sets the function unregistering the subscription from its context.
+func (s *AdjEventSub) setStop(stop func() bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopCtx = stop
+}
+
+// pollEvents reads the channel and derives an event from the change of its
+// control since the last read.
+func (s *AdjEventSub) pollEvents(ctx context.Context) ([]event.PerunEvent, error) {
+	s.log.Log().Debugf("Polling channel %x", s.cid)
+	chanInfo, err := s.hub.cb.GetChannelInfo(ctx, s.hub.perunAddr, s.cid)
 	if err != nil {
-		s.subErrors <- err
-	}
-
-	s.chanControl = chanControl.Control
-	finish := func(err error) {
-		s.err = err
-		close(s.events)
-	}
-	var newChanControl wire.Control
-polling:
-	for {
-		s.log.Log().Debug("AdjudicatorSub is listening for Adjudicator Events")
-		select {
-		case err := <-s.subErrors:
-			finish(err)
-			return
-		case <-ctx.Done():
-			s.log.Log().Debug("Timeout during Adjudicator Subscription")
-			finish(nil)
-			return
-		case <-time.After(s.pollInterval):
-			log.Println("Polling for contract events...", s.cid)
-			newChanInfo, err := s.cb.GetChannelInfo(ctx, s.perunAddr, s.cid)
-			newChanControl = newChanInfo.Control
-
-			if err != nil {
-				s.subErrors <- err
-			}
-			adjEvent, err := DifferencesInControls(s.chanControl, newChanControl)
-			if err != nil {
-				s.subErrors <- err
-			}
-
-			if adjEvent == nil {
-				s.chanControl = newChanControl
-				s.log.Log().Debug("No events yet, continuing polling...")
-				continue polling
-			} else {
-				s.log.Log().Debug("Contract event detected, evaluating...")
-				s.log.Log().Debugf("Found contract event: %v", adjEvent)
-				adjEvent.SetID(s.cid)
-				s.events <- adjEvent
-				etype, _ := adjEvent.GetType()
-				if etype == event.EventTypeWithdrawn {
-					log.Println("Withdrawn event detected, closing subscription")
-					return
-				}
-			}
-		}
+		return nil, readError{err}
+	}
+	adjEvent, err := DifferencesInControls(s.chanControl, chanInfo.Control)
+	if err != nil {
+		return nil, err
+	}
+	s.chanControl = chanInfo.Control
+	if adjEvent == nil {
+		return nil, nil
+	}
+	// The event carries the channel read, like the events of the contract.
+	ev, err := event.NewPerunEvent(polledEventType(adjEvent), chanInfo)
+	if err != nil {
+		return nil, err
+	}
+	ev.SetID(s.cid)
+	return []event.PerunEvent{ev}, nil
+}
+
+// polledEventType returns the type of an event derived by
+// DifferencesInControls.
+func polledEventType(ev event.PerunEvent) event.EventType {
+	switch ev.(type) {
+	case *event.FundEvent:
+		return event.EventTypeFundChannel
+	case *event.CloseEvent:
+		return event.EventTypeClosed
+	case *event.WithdrawnEvent:
+		return event.EventTypeWithdrawn
+	case *event.DisputedEvent:
+		return event.EventTypeDisputed
+	default:
+		return event.EventTypeError
 	}
 }
 
@@ -18,8 +18,10 @@ package channel_test
 
 import (
 	"log"
+	"math/big"
 	"testing"
 
+	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/require"
 	pchannel "perun.network/go-perun/channel"
 	pwallet "perun.network/go-perun/wallet"
@@ -131,7 +133,6 @@ func TestHappyChannelOneWithdrawer(t *testing.T) {
 
 	// Withdrawal
 	{
-		adjAlice := setup.GetAdjudicators()[0]
 		adjBob := setup.GetAdjudicators()[1]
 
 		adjState := perunState
@@ -160,7 +161,7 @@ func TestHappyChannelOneWithdrawer(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NoError(t, adjBob.Withdraw(ctx, reqBob, nil))
-		cb := adjAlice.CB
+		cb := setup.GetStellarClients()[0]
 		tr := cb.GetTransactor()
 		clientAddress, err := tr.GetAddress()
 		if err != nil {
@@ -172,14 +173,14 @@ func TestHappyChannelOneWithdrawer(t *testing.T) {
 		if ok {
 			cAdd0, err := types.MakeContractAddress(tokenAddr0.Asset.ContractID())
 			require.NoError(t, err)
-			bal0, err = cb.GetBalance(cAdd0)
+			bal0, err = cb.GetBalance(ctx, cAdd0)
 			require.NoError(t, err)
 		}
 		tokenAddr1, ok := reqBob.Tx.State.Assets[1].(*types.StellarAsset)
 		if ok {
 			cAdd1, err := types.MakeContractAddress(tokenAddr1.Asset.ContractID())
 			require.NoError(t, err)
-			bal1, err = cb.GetBalance(cAdd1)
+			bal1, err = cb.GetBalance(ctx, cAdd1)
 			require.NoError(t, err)
 		}
 		log.Println("Balance: ", bal0, bal1, " after withdrawing: ", clientAddress, reqBob.Tx.State.Assets)
@@ -187,6 +188,65 @@ func TestHappyChannelOneWithdrawer(t *testing.T) {
 	}
 }
 
+// TestChannel_CloseWithdraws tests that a party closing the channel
+// cooperatively also withdraws its funds.
+func TestChannel_CloseWithdraws(t *testing.T) {
+	setup := chtest.NewTestSetup(t)
+	stellarAsset := setup.GetTokenAsset()
+	accs := setup.GetAccounts()
+	addrList := []pwallet.Address{accs[0].Address(), accs[1].Address()}
+	perunParams, perunState := chtest.NewParamsWithAddressStateWithAsset(t, addrList, stellarAsset)
+
+	freqs := []*pchannel.FundingReq{
+		pchannel.NewFundingReq(perunParams, perunState, 0, perunState.Balances),
+		pchannel.NewFundingReq(perunParams, perunState, 1, perunState.Balances),
+	}
+	ctx := setup.NewCtx(chtest.DefaultTestTimeout)
+	require.NoError(t, chtest.FundAll(ctx, setup.GetFunders(), freqs))
+
+	next := perunState.Clone()
+	next.Version++
+	next.IsFinal = true
+	ethState := channel.ToEthState(next)
+	bytes, err := channel.EncodeEthState(&ethState)
+	require.NoError(t, err)
+	signAlice, err := accs[0].SignData(bytes)
+	require.NoError(t, err)
+	signBob, err := accs[1].SignData(bytes)
+	require.NoError(t, err)
+	reqAlice := pchannel.AdjudicatorReq{
+		Params: perunParams,
+		Tx:     pchannel.Transaction{State: next, Sigs: []pwallet.Sig{signAlice, signBob}},
+		Acc:    map[pwallet.BackendID]pwallet.Account{wtypes.StellarBackendID: accs[0]},
+		Idx:    pchannel.Index(0),
+	}
+
+	cb := setup.GetStellarClients()[0]
+	tokens := make([]xdr.ScAddress, len(next.Assets))
+	before := make([]*big.Int, len(next.Assets))
+	for i, asset := range next.Assets {
+		stellarAsset, ok := asset.(*types.StellarAsset)
+		require.True(t, ok)
+		tokens[i], err = types.MakeContractAddress(stellarAsset.Asset.ContractID())
+		require.NoError(t, err)
+		before[i], err = cb.TokenBalance(ctx, tokens[i])
+		require.NoError(t, err)
+	}
+
+	adjAlice := setup.GetAdjudicators()[0]
+	require.NoError(t, adjAlice.Withdraw(ctx, reqAlice, nil))
+
+	chanInfo, err := adjAlice.CB.GetChannelInfo(ctx, adjAlice.GetPerunAddr(), next.ID)
+	require.NoError(t, err)
+	require.True(t, chanInfo.Control.Closed)
+	require.True(t, chanInfo.Control.WithdrawnA, "closing must withdraw the funds of the closing party")
+	for i, token := range tokens {
+		after, err := cb.TokenBalance(ctx, token)
+		require.NoError(t, err)
+		require.Equal(t, new(big.Int).Add(before[i], next.Balances[i][0]), after, "asset %d", i)
+	}
+}
+
 // TestChannel_RegisterFinal tests the RegisterFinal method of the adjudicator.
 func TestChannel_RegisterFinal(t *testing.T) {
 	setup := chtest.NewTestSetup(t)
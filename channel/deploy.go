@@ -15,70 +15,32 @@
 package channel
 
 import (
-	"crypto/sha256"
 	"os"
 
 	"github.com/stellar/go/txnbuild"
-	"github.com/stellar/go/xdr"
+
+	"perun.network/perun-stellar-backend/client"
 )
 
 const PerunContractPath = "../testdata/perun_soroban_multi_contract.wasm"
 
-// AssembleInstallContractCodeOp assembles the operation to install a contract code.
-func AssembleInstallContractCodeOp(sourceAccount string, wasmFileName string) *txnbuild.InvokeHostFunction {
-	// Assemble the InvokeHostFunction UploadContractWasm operation:
-	// CAP-0047 - https://github.com/stellar/stellar-protocol/blob/master/core/cap-0047.md#creating-a-contract-using-invokehostfunctionop
-
-	contract, err := os.ReadFile(wasmFileName)
+// AssembleInstallContractCodeOp assembles the operation to install the
+// contract code read from the wasm file.
+func AssembleInstallContractCodeOp(sourceAccount string, wasmFileName string) (*txnbuild.InvokeHostFunction, error) {
+	wasm, err := os.ReadFile(wasmFileName)
 	if err != nil {
-		panic(err)
-	}
-
-	return &txnbuild.InvokeHostFunction{
-		HostFunction: xdr.HostFunction{
-			Type: xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm,
-			Wasm: &contract,
-		},
-		SourceAccount: sourceAccount,
+		return nil, err
 	}
+	return client.BuildUploadWasmOp(sourceAccount, wasm), nil
 }
 
-// AssembleCreateContractOp assembles the operation to create a contract.
-func AssembleCreateContractOp(sourceAccount string, wasmFileName string, contractSalt string, passPhrase string) *txnbuild.InvokeHostFunction {
-	// Assemble the InvokeHostFunction CreateContract operation:
-	// CAP-0047 - https://github.com/stellar/stellar-protocol/blob/master/core/cap-0047.md#creating-a-contract-using-invokehostfunctionop
-
-	contract, err := os.ReadFile(wasmFileName)
+// AssembleCreateContractOp assembles the operation to create a contract of
+// the code read from the wasm file. The salt is derived from contractSalt
+// using client.SaltFromString.
+func AssembleCreateContractOp(sourceAccount string, wasmFileName string, contractSalt string) (*txnbuild.InvokeHostFunction, error) {
+	wasm, err := os.ReadFile(wasmFileName)
 	if err != nil {
-		panic(err)
-	}
-
-	salt := sha256.Sum256([]byte(contractSalt))
-	saltParameter := xdr.Uint256(salt)
-
-	accountID := xdr.MustAddress(sourceAccount)
-	contractHash := xdr.Hash(sha256.Sum256(contract))
-
-	return &txnbuild.InvokeHostFunction{
-		HostFunction: xdr.HostFunction{
-			Type: xdr.HostFunctionTypeHostFunctionTypeCreateContract,
-			CreateContract: &xdr.CreateContractArgs{
-				ContractIdPreimage: xdr.ContractIdPreimage{
-					Type: xdr.ContractIdPreimageTypeContractIdPreimageFromAddress,
-					FromAddress: &xdr.ContractIdPreimageFromAddress{
-						Address: xdr.ScAddress{
-							Type:      xdr.ScAddressTypeScAddressTypeAccount,
-							AccountId: &accountID,
-						},
-						Salt: saltParameter,
-					},
-				},
-				Executable: xdr.ContractExecutable{
-					Type:     xdr.ContractExecutableTypeContractExecutableWasm,
-					WasmHash: &contractHash,
-				},
-			},
-		},
-		SourceAccount: sourceAccount,
+		return nil, err
 	}
+	return client.BuildCreateContractOp(sourceAccount, client.WasmHash(wasm), client.SaltFromString(contractSalt))
 }
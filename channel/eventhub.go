@@ -0,0 +1,335 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/xdr"
+	pchannel "perun.network/go-perun/channel"
+	log "perun.network/go-perun/log"
+
+	"perun.network/perun-stellar-backend/client"
+	"perun.network/perun-stellar-backend/event"
+)
+
+// EventHub serves the subscriptions of all channels of a Perun contract from
+// a single loop. If the Invoker is a client.EventSource, the loop streams the
+// events of the contract once per interval and demultiplexes them by channel
+// ID. Otherwise, or if the events were pruned, the subscribed channels are
+// polled.
+//
+// The events of a subscription are queued without limit, so that the hub
+// never waits for a subscription and a slow subscription neither holds back
+// the events of the others nor loses its own. As a channel emits only a few
+// events, the queue stays small.
+//
+// If the events or channels cannot be read, the loop retries them with an
+// increasing interval, without losing events. Only errors that are not caused
+// by reading, e.g., inconsistent channels, end the affected subscriptions.
+//
+// The loop runs while there are subscriptions and until the hub is closed.
+type EventHub struct {
+	cb           client.Invoker
+	source       client.EventSource
+	streaming    bool
+	perunAddr    xdr.ScAddress
+	pollInterval time.Duration
+	log          log.Embedding
+
+	// tickMu serializes the iterations of the loop and the initialization of
+	// subscriptions, so that no event is missed between both.
+	tickMu   sync.Mutex
+	cursor   client.EventCursor
+	failures int
+
+	mu      sync.Mutex
+	subs    map[*AdjEventSub]struct{}
+	running bool
+	// stop cancels the context of the running loop.
+	stop context.CancelFunc
+}
+
+// NewEventHub creates an EventHub for the Perun contract at perunAddr that
+// polls or streams its events in the given interval.
+func NewEventHub(cb client.Invoker, perunAddr xdr.ScAddress, pollInterval time.Duration) *EventHub {
+	h := &EventHub{
+		cb:           cb,
+		perunAddr:    perunAddr,
+		pollInterval: pollInterval,
+		subs:         make(map[*AdjEventSub]struct{}),
+		log:          log.MakeEmbedding(log.Default()),
+	}
+	if source, ok := cb.(client.EventSource); ok {
+		h.source = source
+	}
+	return h
+}
+
+// Subscribe subscribes to the events of the channel. The subscription ends
+// when the channel was withdrawn, the context is done or it is closed.
+func (h *EventHub) Subscribe(ctx context.Context, cid pchannel.ID) (*AdjEventSub, error) {
+	h.tickMu.Lock()
+	defer h.tickMu.Unlock()
+
+	// The event cursor and the initial control are read before the
+	// subscription is added, so that no change after subscribing is missed.
+	h.mu.Lock()
+	running := h.running
+	h.mu.Unlock()
+	if !running && h.source != nil {
+		cursor, err := h.source.LatestEventCursor(ctx)
+		if err != nil {
+			h.log.Log().Warnf("Could not subscribe to contract events, polling the channels: %v", err)
+		}
+		h.cursor, h.streaming = cursor, err == nil
+	}
+	chanInfo, err := h.cb.GetChannelInfo(ctx, h.perunAddr, cid)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := newAdjEventSub(h, cid, chanInfo.Control)
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	if !h.running {
+		runCtx, stop := context.WithCancel(context.Background())
+		h.running, h.stop, h.failures = true, stop, 0
+		go h.run(runCtx)
+	}
+	h.mu.Unlock()
+
+	sub.setStop(context.AfterFunc(ctx, func() { sub.Close() }))
+	return sub, nil
+}
+
+// Close ends all subscriptions of the hub and stops its loop, cancelling
+// pending reads.
+func (h *EventHub) Close() {
+	h.mu.Lock()
+	if h.running {
+		h.stop()
+		h.running, h.stop = false, nil
+	}
+	h.mu.Unlock()
+	for _, sub := range h.subscriptions() {
+		sub.Close()
+	}
+}
+
+// remove removes the subscription from the hub.
+func (h *EventHub) remove(sub *AdjEventSub) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subs, sub)
+}
+
+// subscriptions returns the current subscriptions.
+func (h *EventHub) subscriptions() []*AdjEventSub {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subs := make([]*AdjEventSub, 0, len(h.subs))
+	for sub := range h.subs {
+		subs = append(subs, sub)
+	}
+	return subs
+}
+
+func (h *EventHub) run(ctx context.Context) {
+	h.log.Log().Info("Listening for channel state changes")
+	timer := time.NewTimer(h.retryInterval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return
+		}
+		if !h.tick(ctx) {
+			return
+		}
+		timer.Reset(h.retryInterval())
+	}
+}
+
+// maxRetryShift limits the interval between retries to 2^maxRetryShift times
+// the polling interval.
+const maxRetryShift = 3
+
+// retryInterval returns the polling interval, doubled for each iteration in a
+// row that failed to read.
+func (h *EventHub) retryInterval() time.Duration {
+	h.tickMu.Lock()
+	defer h.tickMu.Unlock()
+	return h.pollInterval << min(h.failures, maxRetryShift)
+}
+
+// readError is an error reading a channel from the ledger. The
+// read is retried, as the RPC might be unavailable only temporarily.
+type readError struct {
+	err error
+}
+
+func (e readError) Error() string {
+	return "reading channel: " + e.err.Error()
+}
+
+func (e readError) Unwrap() error {
+	return e.err
+}
+
+// tick reads and dispatches the events of all subscriptions. It reports false
+// and stops the loop if there are no subscriptions left.
+func (h *EventHub) tick(ctx context.Context) bool {
+	h.tickMu.Lock()
+	defer h.tickMu.Unlock()
+
+	if ctx.Err() != nil {
+		// The hub was closed.
+		return false
+	}
+	subs := h.subscriptions()
+	if len(subs) == 0 {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if len(h.subs) == 0 {
+			h.stop()
+			h.running, h.stop = false, nil
+			return false
+		}
+		return true
+	}
+	h.log.Log().Debugf("EventHub is listening for events of %d subscriptions", len(subs))
+
+	failed := false
+	if !h.streaming {
+		for _, sub := range subs {
+			failed = h.poll(ctx, sub) || failed
+		}
+		h.failed(failed)
+		return true
+	}
+
+	evs, undecoded, err := h.streamEvents(ctx)
+	if err != nil {
+		// The cursor is kept, so that the events are read in the next
+		// iteration.
+		h.log.Log().Warnf("Reading contract events failed, retrying: %v", err)
+		h.failed(true)
+		return true
+	}
+	for _, sub := range subs {
+		subEvs := evs[sub.cid]
+		if len(subEvs) > 0 {
+			sub.chanControl = subEvs[len(subEvs)-1].GetChannel().Control
+		}
+		// Events that cannot be decoded may belong to any channel.
+		if h.dispatch(sub, subEvs, nil) && (undecoded || sub.repoll) {
+			failed = h.poll(ctx, sub) || failed
+		}
+	}
+	h.failed(failed)
+	return true
+}
+
+// poll polls the channel of the subscription and dispatches the events. It
+// reports whether the channel could not be read, in which case it is polled
+// again in the next iteration. Other errors end the subscription.
+func (h *EventHub) poll(ctx context.Context, sub *AdjEventSub) bool {
+	evs, err := sub.pollEvents(ctx)
+	var rerr readError
+	if errors.As(err, &rerr) {
+		h.log.Log().Warnf("Polling channel %x failed, retrying: %v", sub.cid, err)
+		sub.repoll = true
+		return true
+	}
+	sub.repoll = false
+	h.dispatch(sub, evs, err)
+	return false
+}
+
+// failed records whether the iteration failed to read, see retryInterval.
+func (h *EventHub) failed(failed bool) {
+	if failed {
+		h.failures++
+	} else {
+		h.failures = 0
+	}
+}
+
+// streamEvents returns the events emitted since the cursor by channel. If
+// the events were pruned, the cursor is reset to the latest ledger and
+// undecoded is reported, so that the channels are polled instead. Undecoded
+// is also reported if the events contain events that cannot be decoded.
+func (h *EventHub) streamEvents(ctx context.Context) (evs map[pchannel.ID][]event.PerunEvent, undecoded bool, err error) {
+	raw, next, err := h.source.PerunEvents(ctx, h.perunAddr, h.cursor)
+	if errors.Is(err, client.ErrEventsPruned) {
+		h.log.Log().Warnf("Contract events were pruned, polling the channels: %v", err)
+		cursor, err := h.source.LatestEventCursor(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		h.cursor = cursor
+		return nil, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	h.cursor = next
+
+	evs = make(map[pchannel.ID][]event.PerunEvent)
+	for _, ev := range raw {
+		decoded, err := event.DecodeContractEvents([]xdr.ContractEvent{ev}, h.perunAddr)
+		if err != nil || len(decoded) == 0 {
+			h.log.Log().Debugf("Skipping contract event that cannot be decoded: %v", err)
+			undecoded = true
+			continue
+		}
+		for _, d := range decoded {
+			var cid pchannel.ID
+			if len(d.GetChannel().State.ChannelID) != len(cid) {
+				continue
+			}
+			copy(cid[:], d.GetChannel().State.ChannelID)
+			d.SetID(cid)
+			evs[cid] = append(evs[cid], d)
+		}
+	}
+	return evs, undecoded, nil
+}
+
+// dispatch queues the events of the subscription. The subscription ends with
+// err if err is not nil, and after the channel was withdrawn. It reports
+// whether the subscription continues.
+func (h *EventHub) dispatch(sub *AdjEventSub, evs []event.PerunEvent, err error) bool {
+	if err != nil {
+		sub.finish(err)
+		return false
+	}
+	for i, ev := range evs {
+		h.log.Log().Debugf("Found contract event: %v", ev)
+		if etype, _ := ev.GetType(); etype == event.EventTypeWithdrawn {
+			h.log.Log().Debug("Withdrawn event detected, closing subscription")
+			sub.push(evs[:i+1]...)
+			sub.finish(nil)
+			return false
+		}
+	}
+	sub.push(evs...)
+	return true
+}
@@ -33,19 +33,23 @@ import (
 const (
 	MaxIterationsUntilAbort = 30
 	DefaultPollingInterval  = time.Duration(4) * time.Second
+	// DefaultAbortTimeout bounds aborting the funding after the funding
+	// context is done.
+	DefaultAbortTimeout = 30 * time.Second
 )
 
 // Funder is a struct that implements the Funder interface for Stellar.
 type Funder struct {
-	cb              *client.ContractBackend
+	cb              client.Invoker
 	perunAddr       xdr.ScAddress
 	assetAddrs      []xdr.ScVal
 	maxIters        int
 	pollingInterval time.Duration
+	ttlKeeper       *client.TTLKeeper
 }
 
 // NewFunder returns a new Funder.
-func NewFunder(acc *wallet.Account, contractBackend *client.ContractBackend, perunAddr xdr.ScAddress, assetAddrs []xdr.ScVal) *Funder {
+func NewFunder(acc *wallet.Account, contractBackend client.Invoker, perunAddr xdr.ScAddress, assetAddrs []xdr.ScVal) *Funder {
 	return &Funder{
 		cb:              contractBackend,
 		perunAddr:       perunAddr,
@@ -65,6 +69,17 @@ func (f *Funder) GetAssetAddrs() []xdr.ScVal {
 	return f.assetAddrs
 }
 
+// SetTTLKeeper sets a TTLKeeper that keeps opened channels alive on the ledger.
+func (f *Funder) SetTTLKeeper(k *client.TTLKeeper) {
+	f.ttlKeeper = k
+}
+
+// SetPollingInterval sets the interval in which the funder polls the channel
+// while waiting for the other party.
+func (f *Funder) SetPollingInterval(d time.Duration) {
+	f.pollingInterval = d
+}
+
 // Fund first calls open if the channel is not opened and then funds the channel.
 func (f *Funder) Fund(ctx context.Context, req pchannel.FundingReq) error {
 	log.Println("Fund called")
@@ -73,6 +88,12 @@ func (f *Funder) Fund(ctx context.Context, req pchannel.FundingReq) error {
 		return errors.New("req.Idx must be 0 or 1")
 	}
 
+	// The party must be able to receive all assets of the channel when
+	// withdrawing, so trustlines are checked before anything is locked.
+	if err := f.cb.CheckTrustlines(ctx, req.State.Assets); err != nil {
+		return err
+	}
+
 	if req.Idx == pchannel.Index(0) {
 		err := f.openChannel(ctx, req)
 		if err != nil {
@@ -80,6 +101,11 @@ func (f *Funder) Fund(ctx context.Context, req pchannel.FundingReq) error {
 		}
 	}
 
+	// The channel is kept alive from its opening on, so that the funds of a
+	// party are not archived while the other party funds.
+	if f.ttlKeeper != nil {
+		f.ttlKeeper.Track(req.State.ID)
+	}
 	return f.fundParty(ctx, req)
 }
 
@@ -92,13 +118,15 @@ func (f *Funder) fundParty(ctx context.Context, req pchannel.FundingReq) error {
 	for i := 0; i < f.maxIters; i++ {
 		select {
 		case <-ctx.Done():
-			timeoutErr := makeTimeoutErr([]pchannel.Index{req.Idx}, 0)
-			errAbort := f.AbortChannel(ctx, req.State)
 			log.Printf("%s: Aborting channel due to timeout...", party)
-			if errAbort != nil {
-				return errAbort
+			// The funding context is done already, so the abort runs under
+			// a context of its own.
+			abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultAbortTimeout)
+			defer cancel()
+			if err := f.AbortChannel(abortCtx, req.State); err != nil {
+				log.Printf("%s: Error while aborting channel: %v", party, err)
 			}
-			return timeoutErr
+			return makeTimeoutErr([]pchannel.Index{req.Idx}, 0)
 
 		case <-time.After(f.pollingInterval):
 
@@ -121,40 +149,13 @@ func (f *Funder) fundParty(ctx context.Context, req pchannel.FundingReq) error {
 					return nil
 				}
 				err := f.FundChannel(ctx, req.State, false)
+				if errors.Is(err, client.ErrAlreadyFunded) {
+					continue
+				}
 				if err != nil {
 					return err
 				}
-				bal0 := "bal0"
-				bal1 := "bal1"
-				t0, ok := req.State.Assets[0].(*types.StellarAsset)
-				if ok {
-					cAdd0, err := types.MakeContractAddress(t0.Asset.ContractID())
-					if err != nil {
-						return err
-					}
-					for {
-						bal0, err = f.cb.GetBalance(cAdd0)
-						if err != nil {
-							log.Println("Error while getting balance: ", err)
-						}
-						if bal0 != "" {
-							break
-						}
-						time.Sleep(1 * time.Second) // Wait for a second before retrying
-					}
-				}
-				t1, ok := req.State.Assets[1].(*types.StellarAsset)
-				if ok {
-					cAdd1, err := types.MakeContractAddress(t1.Asset.ContractID())
-					if err != nil {
-						return err
-					}
-					bal1, err = f.cb.GetBalance(cAdd1)
-					if err != nil {
-						log.Println("Error while getting balance: ", err)
-					}
-				}
-				log.Println("Balance A: ", bal0, bal1, " after funding amount: ", req.State.Balances, req.State.Assets)
+				f.logBalances(ctx, party, req.State)
 				continue
 			}
 			//nolint:nestif
@@ -166,34 +167,13 @@ func (f *Funder) fundParty(ctx context.Context, req pchannel.FundingReq) error {
 					return nil
 				}
 				err := f.FundChannel(ctx, req.State, true)
+				if errors.Is(err, client.ErrAlreadyFunded) {
+					continue
+				}
 				if err != nil {
 					return err
 				}
-				bal0 := "bal0"
-				bal1 := "bal1"
-				t0, ok := req.State.Assets[0].(*types.StellarAsset)
-				if ok {
-					cAdd0, err := types.MakeContractAddress(t0.Asset.ContractID())
-					if err != nil {
-						return err
-					}
-					bal0, err = f.cb.GetBalance(cAdd0)
-					if err != nil {
-						log.Println("Error while getting balance: ", err)
-					}
-				}
-				t1, ok := req.State.Assets[1].(*types.StellarAsset)
-				if ok {
-					cAdd1, err := types.MakeContractAddress(t1.Asset.ContractID())
-					if err != nil {
-						return err
-					}
-					bal1, err = f.cb.GetBalance(cAdd1)
-					if err != nil {
-						log.Println("Error while getting balance: ", err)
-					}
-				}
-				log.Println("Balance B: ", bal0, bal1, " after funding amount: ", req.State.Balances, req.State.Assets)
+				f.logBalances(ctx, party, req.State)
 				continue
 			}
 		}
@@ -201,9 +181,19 @@ func (f *Funder) fundParty(ctx context.Context, req pchannel.FundingReq) error {
 	return f.AbortChannel(ctx, req.State)
 }
 
+// logBalances logs the token balances of the party after funding.
+func (f *Funder) logBalances(ctx context.Context, party string, state *pchannel.State) {
+	bals, err := client.AssetBalances(ctx, f.cb, state.Assets)
+	if err != nil {
+		log.Println("Error while getting balance: ", err)
+		return
+	}
+	log.Printf("%s: Balance %v after funding amount: %v %v", party, bals, state.Balances, state.Assets)
+}
+
 func (f *Funder) openChannel(ctx context.Context, req pchannel.FundingReq) error {
 	err := f.cb.Open(ctx, f.perunAddr, req.Params, req.State)
-	if err != nil {
+	if err != nil && !errors.Is(err, client.ErrChannelAlreadyExists) {
 		return errors.Join(errors.New("error while opening channel in party A"), err)
 	}
 	_, err = f.cb.GetChannelInfo(ctx, f.perunAddr, req.State.ID)
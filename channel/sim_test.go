@@ -0,0 +1,546 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+	pchannel "perun.network/go-perun/channel"
+	pwallet "perun.network/go-perun/wallet"
+
+	"perun.network/perun-stellar-backend/channel"
+	chtest "perun.network/perun-stellar-backend/channel/test"
+	"perun.network/perun-stellar-backend/client"
+	"perun.network/perun-stellar-backend/wire"
+)
+
+const simTestTimeout = 10 * time.Second
+
+func simCtx(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), simTestTimeout)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func withdrawReq(params *pchannel.Params, state *pchannel.State, sigs []pwallet.Sig, idx pchannel.Index) pchannel.AdjudicatorReq {
+	return pchannel.AdjudicatorReq{
+		Params: params,
+		Tx:     pchannel.Transaction{State: state, Sigs: sigs},
+		Idx:    idx,
+	}
+}
+
+// balances returns the balances of all participants, indexed by asset and
+// participant like the balances of a state.
+func balances(t *testing.T, setup *chtest.SimSetup) pchannel.Balances {
+	t.Helper()
+	bals := make(pchannel.Balances, len(setup.Assets))
+	for i, asset := range setup.Assets {
+		for part := range setup.Accs {
+			bals[i] = append(bals[i], setup.Balance(t, part, asset))
+		}
+	}
+	return bals
+}
+
+// transfer returns the next state, in which A sent amount of the first asset to B.
+func transfer(state *pchannel.State, amount int64, final bool) *pchannel.State {
+	next := state.Clone()
+	next.Version++
+	next.IsFinal = final
+	next.Balances[0][0] = new(big.Int).Sub(next.Balances[0][0], big.NewInt(amount))
+	next.Balances[0][1] = new(big.Int).Add(next.Balances[0][1], big.NewInt(amount))
+	return next
+}
+
+// countingInvoker counts the reads of the channel, i.e., the polls of
+// subscriptions.
+type countingInvoker struct {
+	*chtest.SimInvoker
+	mu    sync.Mutex
+	reads int
+}
+
+func (c *countingInvoker) GetChannelInfo(ctx context.Context, perunAddr xdr.ScAddress, chanID pchannel.ID) (wire.Channel, error) {
+	c.mu.Lock()
+	c.reads++
+	c.mu.Unlock()
+	return c.SimInvoker.GetChannelInfo(ctx, perunAddr, chanID)
+}
+
+func (c *countingInvoker) Reads() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reads
+}
+
+// abortingInvoker records the calls of abort_funding and fails them like an
+// RPC call if their context is done.
+type abortingInvoker struct {
+	*chtest.SimInvoker
+	mu     sync.Mutex
+	aborts int
+}
+
+func (a *abortingInvoker) Abort(ctx context.Context, perunAddr xdr.ScAddress, state *pchannel.State) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.aborts++
+	a.mu.Unlock()
+	return a.SimInvoker.Abort(ctx, perunAddr, state)
+}
+
+func (a *abortingInvoker) Aborts() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.aborts
+}
+
+// flakyInvoker fails the reads of the contract events and channels like an
+// unavailable RPC while they are set down.
+type flakyInvoker struct {
+	*chtest.SimInvoker
+	mu                   sync.Mutex
+	eventsDown, readDown bool
+}
+
+var errRPCDown = errors.New("rpc unavailable")
+
+func (f *flakyInvoker) setDown(events, read bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.eventsDown, f.readDown = events, read
+}
+
+func (f *flakyInvoker) down() (events, read bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.eventsDown, f.readDown
+}
+
+func (f *flakyInvoker) LatestEventCursor(ctx context.Context) (client.EventCursor, error) {
+	if events, _ := f.down(); events {
+		return client.EventCursor{}, errRPCDown
+	}
+	return f.SimInvoker.LatestEventCursor(ctx)
+}
+
+func (f *flakyInvoker) PerunEvents(ctx context.Context, perunAddr xdr.ScAddress, cursor client.EventCursor,
+) ([]xdr.ContractEvent, client.EventCursor, error) {
+	if events, _ := f.down(); events {
+		return nil, client.EventCursor{}, errRPCDown
+	}
+	return f.SimInvoker.PerunEvents(ctx, perunAddr, cursor)
+}
+
+func (f *flakyInvoker) GetChannelInfo(ctx context.Context, perunAddr xdr.ScAddress, chanID pchannel.ID) (wire.Channel, error) {
+	if _, read := f.down(); read {
+		return wire.Channel{}, errRPCDown
+	}
+	return f.SimInvoker.GetChannelInfo(ctx, perunAddr, chanID)
+}
+
+// blockingInvoker blocks the reads of the contract events until their
+// context is done.
+type blockingInvoker struct {
+	*chtest.SimInvoker
+	entered, returned chan struct{}
+}
+
+func (b *blockingInvoker) PerunEvents(ctx context.Context, _ xdr.ScAddress, cursor client.EventCursor,
+) ([]xdr.ContractEvent, client.EventCursor, error) {
+	select {
+	case b.entered <- struct{}{}:
+	default:
+	}
+	<-ctx.Done()
+	select {
+	case b.returned <- struct{}{}:
+	default:
+	}
+	return nil, cursor, ctx.Err()
+}
+
+// countingAdjudicator returns an adjudicator of Alice whose invoker counts
+// the reads of the channel.
+func countingAdjudicator(setup *chtest.SimSetup, pollInterval time.Duration) (*channel.Adjudicator, *countingInvoker) {
+	inv := &countingInvoker{SimInvoker: setup.Contract.Invoker(setup.Addrs[0])}
+	adj := channel.NewAdjudicator(setup.Accs[0], inv, setup.Contract.Address(), setup.Adjs[0].GetAssetAddrs(), false)
+	adj.SetSubscriptionPollingInterval(pollInterval)
+	return adj, inv
+}
+
+// requireRegistered requires that the event is the RegisteredEvent of the
+// registered state, whose timeout is the end of the challenge duration on-chain.
+func requireRegistered(t *testing.T, setup *chtest.SimSetup, ev pchannel.AdjudicatorEvent, registered *pchannel.State) {
+	t.Helper()
+	require.IsType(t, &pchannel.RegisteredEvent{}, ev)
+	regEv := ev.(*pchannel.RegisteredEvent) //nolint:forcetypeassert
+	require.Equal(t, registered.ID, regEv.ID())
+	require.Equal(t, registered.Version, regEv.Version())
+	require.NotNil(t, regEv.State)
+	require.Equal(t, registered.ID, regEv.State.ID)
+	require.Equal(t, registered.Version, regEv.State.Version)
+	require.Equal(t, registered.IsFinal, regEv.State.IsFinal)
+	require.NoError(t, regEv.State.Balances.AssertEqual(registered.Balances))
+
+	ch, err := setup.Channel(registered.ID)
+	require.NoError(t, err)
+	expiry := time.Unix(int64(ch.Control.Timestamp)+chtest.SimChallengeDuration, 0)
+	require.Equal(t, &pchannel.TimeTimeout{Time: expiry}, regEv.Timeout())
+}
+
+func TestSim_Funding(t *testing.T) {
+	setup := chtest.NewSimSetup(t, false)
+	params, state := setup.NewParamsAndState(t)
+	before := balances(t, setup)
+
+	require.NoError(t, setup.Fund(simCtx(t), params, state))
+
+	ch, err := setup.Channel(state.ID)
+	require.NoError(t, err)
+	require.True(t, ch.Control.FundedA)
+	require.True(t, ch.Control.FundedB)
+	require.NoError(t, balances(t, setup).AssertEqual(before.Sub(state.Balances)))
+}
+
+func TestSim_CooperativeClose(t *testing.T) {
+	setup := chtest.NewSimSetup(t, false)
+	params, state := setup.NewParamsAndState(t)
+	ctx := simCtx(t)
+	before := balances(t, setup)
+	require.NoError(t, setup.Fund(ctx, params, state))
+
+	final := transfer(state, 50, true) //nolint:gomnd
+	sigs := setup.Sign(t, final)
+	for i, adj := range setup.Adjs {
+		require.NoError(t, adj.Withdraw(ctx, withdrawReq(params, final, sigs, pchannel.Index(i)), nil))
+	}
+
+	_, err := setup.Channel(state.ID)
+	require.ErrorIs(t, err, client.ErrChannelNotFound)
+	want := before.Sub(state.Balances).Add(final.Balances)
+	require.NoError(t, balances(t, setup).AssertEqual(want))
+}
+
+func TestSim_DisputeAndForceClose(t *testing.T) {
+	setup := chtest.NewSimSetup(t, false)
+	params, state := setup.NewParamsAndState(t)
+	ctx := simCtx(t)
+	before := balances(t, setup)
+	require.NoError(t, setup.Fund(ctx, params, state))
+
+	sub, err := setup.Adjs[0].Subscribe(ctx, state.ID)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	next := transfer(state, 30, false) //nolint:gomnd
+	sigs := setup.Sign(t, next)
+	require.NoError(t, setup.Adjs[1].Register(ctx, withdrawReq(params, next, sigs, 1), nil))
+
+	requireRegistered(t, setup, sub.Next(), next)
+
+	// The channel can only be force-closed after the challenge duration.
+	err = setup.Adjs[0].Withdraw(ctx, withdrawReq(params, next, sigs, 0), nil)
+	require.ErrorIs(t, err, client.ErrTimelockNotExpired)
+
+	setup.Contract.AdvanceTime(chtest.SimChallengeDuration * time.Second)
+	for i, adj := range setup.Adjs {
+		require.NoError(t, adj.Withdraw(ctx, withdrawReq(params, next, sigs, pchannel.Index(i)), nil))
+	}
+	want := before.Sub(state.Balances).Add(next.Balances)
+	require.NoError(t, balances(t, setup).AssertEqual(want))
+}
+
+func TestSim_SubscriptionStreamsEvents(t *testing.T) {
+	setup := chtest.NewSimSetup(t, false)
+	params, state := setup.NewParamsAndState(t)
+	ctx := simCtx(t)
+	require.NoError(t, setup.Fund(ctx, params, state))
+
+	adj, inv := countingAdjudicator(setup, chtest.SimPollingInterval)
+	sub, err := adj.Subscribe(ctx, state.ID)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	next := transfer(state, 30, false) //nolint:gomnd
+	sigs := setup.Sign(t, next)
+	require.NoError(t, setup.Adjs[1].Register(ctx, withdrawReq(params, next, sigs, 1), nil))
+	require.IsType(t, &pchannel.RegisteredEvent{}, sub.Next())
+	require.Equal(t, 1, inv.Reads(), "the dispute must be streamed without polling the channel")
+
+	setup.Contract.AdvanceTime(chtest.SimChallengeDuration * time.Second)
+	require.NoError(t, setup.Adjs[1].Withdraw(ctx, withdrawReq(params, next, sigs, 1), nil))
+	ev := sub.Next()
+	require.IsType(t, &pchannel.ConcludedEvent{}, ev)
+	require.Equal(t, state.ID, ev.ID())
+	require.Equal(t, 1, inv.Reads(), "the force close must be streamed without polling the channel")
+}
+
+func TestSim_SubscriptionFallsBackOnPrunedEvents(t *testing.T) {
+	setup := chtest.NewSimSetup(t, false)
+	params, state := setup.NewParamsAndState(t)
+	ctx := simCtx(t)
+	require.NoError(t, setup.Fund(ctx, params, state))
+
+	adj, inv := countingAdjudicator(setup, 50*time.Millisecond) //nolint:gomnd
+	sub, err := adj.Subscribe(ctx, state.ID)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	// The dispute is pruned before the subscription reads it.
+	next := transfer(state, 30, false) //nolint:gomnd
+	require.NoError(t, setup.Adjs[1].Register(ctx, withdrawReq(params, next, setup.Sign(t, next), 1), nil))
+	setup.Contract.PruneEvents()
+
+	requireRegistered(t, setup, sub.Next(), next)
+	require.Greater(t, inv.Reads(), 1, "the channel must be polled after the events were pruned")
+}
+
+func TestSim_EventHubRetriesReads(t *testing.T) {
+	for _, streaming := range []bool{true, false} {
+		t.Run(map[bool]string{true: "streaming", false: "polling"}[streaming], func(t *testing.T) {
+			setup := chtest.NewSimSetup(t, false)
+			params, state := setup.NewParamsAndState(t)
+			ctx := simCtx(t)
+			require.NoError(t, setup.Fund(ctx, params, state))
+
+			inv := &flakyInvoker{SimInvoker: setup.Contract.Invoker(setup.Addrs[0])}
+			// Without events at subscription, the hub polls the channel.
+			inv.setDown(!streaming, false)
+			hub := channel.NewEventHub(inv, setup.Contract.Address(), chtest.SimPollingInterval)
+			sub, err := hub.Subscribe(ctx, state.ID)
+			require.NoError(t, err)
+			defer sub.Close()
+
+			inv.setDown(true, true)
+			next := transfer(state, 30, false) //nolint:gomnd
+			require.NoError(t, setup.Adjs[1].Register(ctx, withdrawReq(params, next, setup.Sign(t, next), 1), nil))
+			time.Sleep(10 * chtest.SimPollingInterval)
+			require.NoError(t, sub.Err(), "failed reads must not end the subscription")
+
+			inv.setDown(false, false)
+			requireRegistered(t, setup, sub.Next(), next)
+		})
+	}
+}
+
+func TestSim_EventHubServesManyChannels(t *testing.T) {
+	setup := chtest.NewSimSetup(t, false)
+	ctx := simCtx(t)
+	adj, inv := countingAdjudicator(setup, chtest.SimPollingInterval)
+
+	const numChannels = 3
+	params := make([]*pchannel.Params, numChannels)
+	states := make([]*pchannel.State, numChannels)
+	subs := make([]pchannel.AdjudicatorSubscription, numChannels)
+	for i := range states {
+		params[i], states[i] = setup.NewParamsAndState(t)
+		require.NoError(t, setup.Fund(ctx, params[i], states[i]))
+	}
+	for i, state := range states {
+		sub, err := adj.Subscribe(ctx, state.ID)
+		require.NoError(t, err)
+		defer sub.Close()
+		subs[i] = sub
+	}
+	for i, state := range states {
+		next := transfer(state, 10, false) //nolint:gomnd
+		require.NoError(t, setup.Adjs[1].Register(ctx, withdrawReq(params[i], next, setup.Sign(t, next), 1), nil))
+	}
+
+	for i, sub := range subs {
+		ev := sub.Next()
+		require.IsType(t, &pchannel.RegisteredEvent{}, ev)
+		require.Equal(t, states[i].ID, ev.ID())
+	}
+	require.Equal(t, numChannels, inv.Reads(), "the channels must only be read on subscription")
+}
+
+func TestSim_EventHubSlowSubscription(t *testing.T) {
+	setup := chtest.NewSimSetup(t, false)
+	ctx := simCtx(t)
+	adj, _ := countingAdjudicator(setup, chtest.SimPollingInterval)
+
+	paramsA, stateA := setup.NewParamsAndState(t)
+	paramsB, stateB := setup.NewParamsAndState(t)
+	require.NoError(t, setup.Fund(ctx, paramsA, stateA))
+	require.NoError(t, setup.Fund(ctx, paramsB, stateB))
+	subA, err := adj.Subscribe(ctx, stateA.ID)
+	require.NoError(t, err)
+	defer subA.Close()
+	subB, err := adj.Subscribe(ctx, stateB.ID)
+	require.NoError(t, err)
+	defer subB.Close()
+
+	// The disputes of A are not read for now.
+	var registered []*pchannel.State
+	for _, amount := range []int64{10, 20} {
+		next := transfer(stateA, amount, false)
+		next.Version += uint64(amount)
+		require.NoError(t, setup.Adjs[1].Register(ctx, withdrawReq(paramsA, next, setup.Sign(t, next), 1), nil))
+		registered = append(registered, next)
+	}
+	nextB := transfer(stateB, 10, false) //nolint:gomnd
+	require.NoError(t, setup.Adjs[1].Register(ctx, withdrawReq(paramsB, nextB, setup.Sign(t, nextB), 1), nil))
+
+	// The slow subscription does not hold back the others.
+	requireRegistered(t, setup, subB.Next(), nextB)
+
+	// The slow subscription keeps all its events.
+	time.Sleep(10 * chtest.SimPollingInterval)
+	for _, next := range registered {
+		requireRegistered(t, setup, subA.Next(), next)
+	}
+	require.NoError(t, subA.Err())
+}
+
+func TestSim_EventHubCloseCancelsReads(t *testing.T) {
+	setup := chtest.NewSimSetup(t, false)
+	params, state := setup.NewParamsAndState(t)
+	ctx := simCtx(t)
+	require.NoError(t, setup.Fund(ctx, params, state))
+
+	inv := &blockingInvoker{
+		SimInvoker: setup.Contract.Invoker(setup.Addrs[0]),
+		entered:    make(chan struct{}, 1),
+		returned:   make(chan struct{}, 1),
+	}
+	hub := channel.NewEventHub(inv, setup.Contract.Address(), chtest.SimPollingInterval)
+	sub, err := hub.Subscribe(ctx, state.ID)
+	require.NoError(t, err)
+
+	select {
+	case <-inv.entered:
+	case <-ctx.Done():
+		t.Fatal("the hub did not read the events")
+	}
+	hub.Close()
+	select {
+	case <-inv.returned:
+	case <-ctx.Done():
+		t.Fatal("closing the hub must cancel pending reads")
+	}
+	require.Nil(t, sub.Next())
+}
+
+func TestSim_OneWithdrawer(t *testing.T) {
+	setup := chtest.NewSimSetup(t, true)
+	params, state := setup.NewParamsAndState(t)
+	ctx := simCtx(t)
+	before := balances(t, setup)
+	require.NoError(t, setup.Fund(ctx, params, state))
+
+	final := transfer(state, 20, true) //nolint:gomnd
+	sigs := setup.Sign(t, final)
+	// Bob closes and withdraws for both.
+	require.NoError(t, setup.Adjs[1].Withdraw(ctx, withdrawReq(params, final, sigs, 1), nil))
+
+	_, err := setup.Channel(state.ID)
+	require.ErrorIs(t, err, client.ErrChannelNotFound)
+	want := before.Sub(state.Balances).Add(final.Balances)
+	require.NoError(t, balances(t, setup).AssertEqual(want))
+}
+
+func TestSim_FundingTimeoutAborts(t *testing.T) {
+	setup := chtest.NewSimSetup(t, false)
+	params, state := setup.NewParamsAndState(t)
+	before := balances(t, setup)
+
+	// Only Alice funds, so her funder times out and aborts the channel.
+	inv := &abortingInvoker{SimInvoker: setup.Contract.Invoker(setup.Addrs[0])}
+	funder := channel.NewFunder(setup.Accs[0], inv, setup.Contract.Address(), setup.Funders[0].GetAssetAddrs())
+	funder.SetPollingInterval(chtest.SimPollingInterval)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond) //nolint:gomnd
+	defer cancel()
+	err := funder.Fund(ctx, *pchannel.NewFundingReq(params, state, 0, state.Balances))
+	require.True(t, pchannel.IsFundingTimeoutError(err), "expected funding timeout, got %v", err)
+	require.Equal(t, 1, inv.Aborts(), "abort_funding must be submitted after the timeout")
+
+	_, err = setup.Channel(state.ID)
+	require.ErrorIs(t, err, client.ErrChannelNotFound)
+	require.NoError(t, balances(t, setup).AssertEqual(before))
+}
+
+//nolint:funlen
+func TestSimContract_Rules(t *testing.T) {
+	setup := chtest.NewSimSetup(t, false)
+	params, state := setup.NewParamsAndState(t)
+	ctx := simCtx(t)
+	perun := setup.Contract.Address()
+	alice := setup.Contract.Invoker(setup.Addrs[0])
+	bob := setup.Contract.Invoker(setup.Addrs[1])
+
+	_, err := alice.GetChannelInfo(ctx, perun, state.ID)
+	require.ErrorIs(t, err, client.ErrChannelNotFound)
+	require.ErrorIs(t, alice.Open(ctx, setup.Addrs[0], params, state), chtest.ErrUnknownContract)
+
+	final := state.Clone()
+	final.IsFinal = true
+	require.ErrorIs(t, alice.Open(ctx, perun, params, final), client.ErrOpenOnFinalState)
+	require.NoError(t, alice.Open(ctx, perun, params, state))
+	require.ErrorIs(t, bob.Open(ctx, perun, params, state), client.ErrChannelAlreadyExists)
+
+	require.ErrorIs(t, alice.Abort(ctx, perun, state), client.ErrAbortFundingWithoutFunds)
+	require.ErrorIs(t, alice.Fund(ctx, perun, state.ID, true), client.ErrInvalidActor)
+	require.NoError(t, alice.Fund(ctx, perun, state.ID, false))
+	require.ErrorIs(t, alice.Fund(ctx, perun, state.ID, false), client.ErrAlreadyFunded)
+
+	next := transfer(state, 10, false) //nolint:gomnd
+	sigs := setup.Sign(t, next)
+	require.ErrorIs(t, alice.Dispute(ctx, perun, next, sigs), client.ErrOperationOnUnfundedChannel)
+	require.NoError(t, bob.Fund(ctx, perun, state.ID, true))
+	require.ErrorIs(t, alice.Abort(ctx, perun, state), client.ErrAbortFundingOnFundedChannel)
+
+	req := withdrawReq(params, next, sigs, 0)
+	require.ErrorIs(t, alice.Withdraw(ctx, perun, req, false, false), client.ErrWithdrawOnOpenChannel)
+	require.ErrorIs(t, alice.ForceClose(ctx, perun, state.ID), client.ErrForceCloseOnUndisputedChannel)
+	require.ErrorIs(t, alice.Close(ctx, perun, next, sigs), client.ErrCloseOnNonFinalState)
+
+	swapped := []pwallet.Sig{sigs[1], sigs[0]}
+	require.ErrorIs(t, alice.Dispute(ctx, perun, next, swapped), client.ErrInvalidSignature)
+	inflated := next.Clone()
+	inflated.Balances[0][0] = new(big.Int).Add(inflated.Balances[0][0], big.NewInt(1))
+	require.ErrorIs(t, alice.Dispute(ctx, perun, inflated, setup.Sign(t, inflated)), client.ErrInvalidStateTransition)
+
+	require.NoError(t, alice.Dispute(ctx, perun, next, sigs))
+	require.ErrorIs(t, bob.Dispute(ctx, perun, next, sigs), client.ErrInvalidVersionNumber)
+	require.ErrorIs(t, alice.Abort(ctx, perun, state), client.ErrAbortFundingOnDisputedChannel)
+	require.ErrorIs(t, alice.ForceClose(ctx, perun, state.ID), client.ErrTimelockNotExpired)
+
+	setup.Contract.AdvanceTime(chtest.SimChallengeDuration * time.Second)
+	require.NoError(t, alice.ForceClose(ctx, perun, state.ID))
+	require.ErrorIs(t, alice.ForceClose(ctx, perun, state.ID), client.ErrForceCloseOnClosedChannel)
+	require.ErrorIs(t, alice.Dispute(ctx, perun, transfer(next, 1, false), setup.Sign(t, transfer(next, 1, false))),
+		client.ErrDisputeOnClosedChannel)
+	require.ErrorIs(t, alice.Withdraw(ctx, perun, req, true, false), client.ErrInvalidActor)
+	require.NoError(t, alice.Withdraw(ctx, perun, req, false, false))
+
+	ch, err := bob.GetChannelInfo(ctx, perun, state.ID)
+	require.NoError(t, err)
+	require.True(t, ch.Control.WithdrawnA)
+	require.False(t, ch.Control.WithdrawnB)
+	require.Equal(t, uint64(next.Version), uint64(ch.State.Version))
+}
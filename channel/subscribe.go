@@ -21,67 +21,83 @@ import (
 	pchannel "perun.network/go-perun/channel"
 
 	"perun.network/perun-stellar-backend/event"
+	"perun.network/perun-stellar-backend/wire"
 )
 
-// Next returns the next event from the event subscription.
+// Next returns the next event from the event subscription. Events that have
+// no corresponding adjudicator event, like the funding of the channel, are
+// skipped.
 func (s *AdjEventSub) Next() pchannel.AdjudicatorEvent {
-	if s.closer.IsClosed() {
-		return nil
-	}
-
-	if s.getEvents() == nil {
-		return nil
-	}
-	select {
-	case ev := <-s.getEvents():
-		if ev == nil {
+	for {
+		if s.closer.IsClosed() {
 			return nil
 		}
-
-		switch e := ev.(type) {
-		case *event.DisputedEvent:
-			log.Println("DisputedEvent received - build RegisteredEvent")
-			dispEvent := pchannel.AdjudicatorEventBase{
-				VersionV: e.Version(),
-				IDV:      e.ID(),
-				TimeoutV: event.MakeTimeout(*s.challengeDuration),
-			}
-			adjDispEvent := &pchannel.RegisteredEvent{AdjudicatorEventBase: dispEvent, State: nil, Sigs: nil}
-			return adjDispEvent
-
-		case *event.CloseEvent:
-
-			log.Println("CloseEvent received - build ConcludedEvent, ", e.ID())
-
-			conclEvent := pchannel.AdjudicatorEventBase{
-				VersionV: e.Version(),
-				IDV:      e.ID(),
-				TimeoutV: event.MakeTimeout(*s.challengeDuration),
+		ev, ok, finished := s.pop()
+		if finished {
+			return nil
+		}
+		if ok {
+			if adjEvent := s.adjudicatorEvent(ev); adjEvent != nil {
+				return adjEvent
 			}
-			adjConclEvent := &pchannel.ConcludedEvent{AdjudicatorEventBase: conclEvent}
-			return adjConclEvent
+			continue
+		}
+		select {
+		case <-s.notify:
+		case <-s.closer.Closed():
+			return nil
+		}
+	}
+}
 
-		default:
-			log.Printf("Received an unknown event type: %v\n", reflect.TypeOf(e))
+// adjudicatorEvent converts the contract event to an adjudicator event. It
+// returns nil if there is no corresponding adjudicator event. The version,
+// timeout and registered state are those of the channel carried by the event.
+func (s *AdjEventSub) adjudicatorEvent(ev event.PerunEvent) pchannel.AdjudicatorEvent {
+	base := pchannel.AdjudicatorEventBase{
+		VersionV: ev.Version(),
+		IDV:      ev.ID(),
+		TimeoutV: ev.Timeout(),
+	}
+	switch e := ev.(type) {
+	case *event.DisputedEvent:
+		log.Println("DisputedEvent received - build RegisteredEvent")
+		state, err := wire.ToState(e.GetChannel().State)
+		if err != nil {
+			log.Printf("Skipping DisputedEvent with invalid state: %v\n", err)
 			return nil
 		}
+		return &pchannel.RegisteredEvent{AdjudicatorEventBase: base, State: &state, Sigs: nil}
 
-	case <-s.closer.Closed():
+	case *event.CloseEvent, *event.ForceClosedEvent:
+		log.Println("CloseEvent received - build ConcludedEvent, ", e.ID())
+		return &pchannel.ConcludedEvent{AdjudicatorEventBase: base}
+
+	default:
+		log.Printf("Skipping event of type %v\n", reflect.TypeOf(e))
 		return nil
 	}
 }
 
-// Close closes the event subscription.
+// Close closes the event subscription and removes it from its EventHub.
 func (s *AdjEventSub) Close() error {
-	s.closer.Close()
+	if s.closer.Close() != nil {
+		// The subscription was already closed.
+		return nil
+	}
+	s.hub.remove(s)
+	s.mu.Lock()
+	stop := s.stopCtx
+	s.mu.Unlock()
+	if stop != nil {
+		stop()
+	}
 	return nil
 }
 
-func (s *AdjEventSub) getEvents() <-chan event.PerunEvent {
-	return s.events
-}
-
 // Err returns the error of the event subscription.
 func (s *AdjEventSub) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.err
 }
@@ -232,7 +232,9 @@ func NewContractBackendsFromKeys(kps []*keypair.Full, acc []pwallet.Account, url
 func NewContractBackendFromKey(kp *keypair.Full, acc *pwallet.Account, url string) *client.ContractBackend {
 	trConfig := client.TransactorConfig{}
 	trConfig.SetKeyPair(kp)
-	trConfig.SetHorizonURL(url)
+	if err := trConfig.SetHorizonURL(url); err != nil {
+		panic(err)
+	}
 	if acc != nil {
 		trConfig.SetAccount(*acc)
 	}
@@ -358,9 +360,12 @@ func CreateFundStellarAccounts(pairs []*keypair.Full, initialBalance string) err
 }
 
 // NewParamsWithAddressStateWithAsset creates a new channel params and state with the given addresses.
-func NewParamsWithAddressStateWithAsset(t *testing.T, partsAddr []pwallet.Address, assets []pchannel.Asset) (*pchannel.Params, *pchannel.State) {
-	rng := pkgtest.Prng(t)
+// The given options override the defaults.
+func NewParamsWithAddressStateWithAsset(t *testing.T, partsAddr []pwallet.Address, assets []pchannel.Asset, opts ...ptest.RandomOpt) (*pchannel.Params, *pchannel.State) {
+	return newParamsWithAddressStateWithAsset(pkgtest.Prng(t), partsAddr, assets, opts...)
+}
 
+func newParamsWithAddressStateWithAsset(rng *mathrand.Rand, partsAddr []pwallet.Address, assets []pchannel.Asset, opts ...ptest.RandomOpt) (*pchannel.Params, *pchannel.State) {
 	numParts := 2
 	partsMapSlice := make([]map[pwallet.BackendID]pwallet.Address, len(partsAddr))
 	for i, addr := range partsAddr {
@@ -380,7 +385,7 @@ func NewParamsWithAddressStateWithAsset(t *testing.T, partsAddr []pwallet.Addres
 		ptest.WithVirtualChannel(false),
 		ptest.WithoutApp(),
 		ptest.WithBalances([]pchannel.Bal{big.NewInt(100), big.NewInt(150)}, []pchannel.Bal{big.NewInt(200), big.NewInt(250)}), //nolint:gomnd
-	))
+	).Append(opts...))
 }
 
 // NewCtx creates a new context with the given test timeout.
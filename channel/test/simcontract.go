@@ -0,0 +1,607 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/xdr"
+	pchannel "perun.network/go-perun/channel"
+	pwallet "perun.network/go-perun/wallet"
+
+	"perun.network/perun-stellar-backend/channel"
+	"perun.network/perun-stellar-backend/channel/types"
+	"perun.network/perun-stellar-backend/client"
+	"perun.network/perun-stellar-backend/event"
+	wtypes "perun.network/perun-stellar-backend/wallet/types"
+	"perun.network/perun-stellar-backend/wire"
+	"perun.network/perun-stellar-backend/wire/scval"
+)
+
+var (
+	// ErrUnknownContract is returned when a SimContract is invoked under a
+	// different address than its own.
+	ErrUnknownContract = errors.New("no contract at the given address")
+	// ErrInsufficientBalance is returned when an account cannot pay its
+	// funding share.
+	ErrInsufficientBalance = errors.New("insufficient token balance")
+)
+
+// SimContract is an in-memory simulation of the Perun contract. Its functions
+// follow the rules of the contract and fail with the same client.ContractError
+// values, so that the channel components can be tested without a Stellar
+// network. Token transfers of Stellar assets are simulated with in-memory
+// balances, assets of other chains are not transferred. Calls take effect
+// immediately and do not observe their context. Like the contract, it emits
+// perun events, which SimInvoker streams as a client.EventSource.
+type SimContract struct {
+	mu       sync.Mutex
+	address  xdr.ScAddress
+	now      uint64
+	channels map[pchannel.ID]*simChannel
+	balances map[simBalanceKey]*big.Int
+	// events holds the retained events, of which the first has the index
+	// firstEvent in the stream of all emitted events.
+	events     []xdr.ContractEvent
+	firstEvent int
+}
+
+type simChannel struct {
+	params  *pchannel.Params
+	state   *pchannel.State
+	channel wire.Channel
+}
+
+type simBalanceKey struct {
+	token   string
+	account string
+}
+
+// NewSimContract creates a new SimContract under a random contract address.
+// Its ledger time starts at the current time.
+func NewSimContract() *SimContract {
+	var contractID xdr.Hash
+	if _, err := rand.Read(contractID[:]); err != nil {
+		panic(err)
+	}
+	address, err := types.MakeContractAddress(contractID)
+	if err != nil {
+		panic(err)
+	}
+	return &SimContract{
+		address:  address,
+		now:      uint64(time.Now().Unix()),
+		channels: make(map[pchannel.ID]*simChannel),
+		balances: make(map[simBalanceKey]*big.Int),
+	}
+}
+
+// Address returns the address of the contract.
+func (c *SimContract) Address() xdr.ScAddress {
+	return c.address
+}
+
+// Invoker returns an Invoker calling the contract on behalf of the given account.
+func (c *SimContract) Invoker(account xdr.ScAddress) *SimInvoker {
+	return &SimInvoker{contract: c, account: account}
+}
+
+// AdvanceTime advances the ledger time of the contract.
+func (c *SimContract) AdvanceTime(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now += uint64(d / time.Second)
+}
+
+// PruneEvents drops all emitted events, like soroban-rpc does once they left
+// its retention window.
+func (c *SimContract) PruneEvents() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.firstEvent += len(c.events)
+	c.events = nil
+}
+
+// Mint credits the account with the given amount of the token.
+func (c *SimContract) Mint(token, account xdr.ScAddress, amount *big.Int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.transfer(token, nil, &account, amount)
+}
+
+// Balance returns the balance of the account in the given token.
+func (c *SimContract) Balance(token, account xdr.ScAddress) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key, err := balanceKey(token, account)
+	if err != nil {
+		return nil, err
+	}
+	if bal, ok := c.balances[key]; ok {
+		return new(big.Int).Set(bal), nil
+	}
+	return new(big.Int), nil
+}
+
+func (c *SimContract) open(perunAddr xdr.ScAddress, params *pchannel.Params, state *pchannel.State) error {
+	if err := c.checkAddress(perunAddr); err != nil {
+		return err
+	}
+	wireParams, err := wire.MakeParams(*params)
+	if err != nil {
+		return errors.Join(client.ErrEncoding, err)
+	}
+	wireState, err := wire.MakeState(*state)
+	if err != nil {
+		return errors.Join(client.ErrEncoding, err)
+	}
+	id, err := channel.Backend.CalcID(params)
+	if err != nil {
+		return errors.Join(client.ErrEncoding, err)
+	}
+	if id != state.ID {
+		return client.ErrChannelIDMismatch
+	}
+	if state.IsFinal {
+		return client.ErrOpenOnFinalState
+	}
+	if _, ok := c.channels[id]; ok {
+		return client.ErrChannelAlreadyExists
+	}
+	c.channels[id] = &simChannel{
+		params:  params.Clone(),
+		state:   state.Clone(),
+		channel: wire.MakeChannel(wireParams, wireState, wire.Control{}),
+	}
+	return c.emit("open", c.channels[id], nil)
+}
+
+func (c *SimContract) fund(actor, perunAddr xdr.ScAddress, chanID pchannel.ID, funderIdx bool) error {
+	ch, err := c.get(perunAddr, chanID)
+	if err != nil {
+		return err
+	}
+	control := &ch.channel.Control
+	if control.Closed || control.Disputed {
+		return client.ErrInvalidStateTransition
+	}
+	idx := partyIndex(funderIdx)
+	if !actor.Equals(ch.party(idx)) {
+		return client.ErrInvalidActor
+	}
+	if (idx == 0 && control.FundedA) || (idx == 1 && control.FundedB) {
+		return client.ErrAlreadyFunded
+	}
+	contract := c.address
+	if err := c.transferShare(ch.state, idx, &actor, &contract); err != nil {
+		return err
+	}
+	if idx == 0 {
+		control.FundedA = true
+	} else {
+		control.FundedB = true
+	}
+	if err := c.emit("fund", ch, &funderIdx); err != nil {
+		return err
+	}
+	if control.FundedA && control.FundedB {
+		return c.emit("fund_c", ch, nil)
+	}
+	return nil
+}
+
+func (c *SimContract) dispute(perunAddr xdr.ScAddress, state *pchannel.State, sigs []pwallet.Sig) error {
+	ch, err := c.get(perunAddr, state.ID)
+	if err != nil {
+		return err
+	}
+	control := &ch.channel.Control
+	if control.Closed {
+		return client.ErrDisputeOnClosedChannel
+	}
+	if !control.FundedA || !control.FundedB {
+		return client.ErrOperationOnUnfundedChannel
+	}
+	if control.Disputed && state.Version <= ch.state.Version {
+		return client.ErrInvalidVersionNumber
+	}
+	if err := ch.update(state, sigs); err != nil {
+		return err
+	}
+	control.Disputed = true
+	control.Timestamp = xdr.Uint64(c.now)
+	return c.emit("dispute", ch, nil)
+}
+
+func (c *SimContract) close(perunAddr xdr.ScAddress, state *pchannel.State, sigs []pwallet.Sig) error {
+	ch, err := c.get(perunAddr, state.ID)
+	if err != nil {
+		return err
+	}
+	control := &ch.channel.Control
+	if control.Closed {
+		return client.ErrInvalidStateTransition
+	}
+	if !state.IsFinal {
+		return client.ErrCloseOnNonFinalState
+	}
+	if !control.FundedA || !control.FundedB {
+		return client.ErrOperationOnUnfundedChannel
+	}
+	if err := ch.update(state, sigs); err != nil {
+		return err
+	}
+	control.Closed = true
+	return c.emit("closed", ch, nil)
+}
+
+func (c *SimContract) forceClose(perunAddr xdr.ScAddress, chanID pchannel.ID) error {
+	ch, err := c.get(perunAddr, chanID)
+	if err != nil {
+		return err
+	}
+	control := &ch.channel.Control
+	if control.Closed {
+		return client.ErrForceCloseOnClosedChannel
+	}
+	if !control.Disputed {
+		return client.ErrForceCloseOnUndisputedChannel
+	}
+	if c.now-uint64(control.Timestamp) < ch.params.ChallengeDuration {
+		return client.ErrTimelockNotExpired
+	}
+	control.Closed = true
+	return c.emit("f_closed", ch, nil)
+}
+
+func (c *SimContract) withdraw(actor, perunAddr xdr.ScAddress, chanID pchannel.ID, withdrawerIdx, oneWithdrawer bool) error {
+	ch, err := c.get(perunAddr, chanID)
+	if err != nil {
+		return err
+	}
+	control := &ch.channel.Control
+	if !control.Closed {
+		return client.ErrWithdrawOnOpenChannel
+	}
+	idx := partyIndex(withdrawerIdx)
+	party := ch.party(idx)
+	if !oneWithdrawer && !actor.Equals(party) {
+		return client.ErrInvalidActor
+	}
+	if (idx == 0 && control.WithdrawnA) || (idx == 1 && control.WithdrawnB) {
+		return client.ErrInvalidStateTransition
+	}
+	contract := c.address
+	if err := c.transferShare(ch.state, idx, &contract, &party); err != nil {
+		return err
+	}
+	if idx == 0 {
+		control.WithdrawnA = true
+	} else {
+		control.WithdrawnB = true
+	}
+	if err := c.emit("withdraw", ch, &withdrawerIdx); err != nil {
+		return err
+	}
+	// Like the contract, the channel is deleted once both parties withdrew.
+	if control.WithdrawnA && control.WithdrawnB {
+		delete(c.channels, chanID)
+		return c.emit("pay_c", ch, nil)
+	}
+	return nil
+}
+
+func (c *SimContract) abortFunding(perunAddr xdr.ScAddress, chanID pchannel.ID) error {
+	ch, err := c.get(perunAddr, chanID)
+	if err != nil {
+		return err
+	}
+	control := ch.channel.Control
+	switch {
+	case control.Closed:
+		return client.ErrAbortFundingOnClosedChannel
+	case control.Disputed:
+		return client.ErrAbortFundingOnDisputedChannel
+	case control.FundedA && control.FundedB:
+		return client.ErrAbortFundingOnFundedChannel
+	case !control.FundedA && !control.FundedB:
+		return client.ErrAbortFundingWithoutFunds
+	}
+	// Refund the party that already funded.
+	idx := pchannel.Index(0)
+	if control.FundedB {
+		idx = 1
+	}
+	contract, party := c.address, ch.party(idx)
+	if err := c.transferShare(ch.state, idx, &contract, &party); err != nil {
+		return err
+	}
+	delete(c.channels, chanID)
+	return nil
+}
+
+func (c *SimContract) getChannel(perunAddr xdr.ScAddress, chanID pchannel.ID) (wire.Channel, error) {
+	ch, err := c.get(perunAddr, chanID)
+	if err != nil {
+		return wire.Channel{}, err
+	}
+	return ch.channel, nil
+}
+
+// emit emits a perun event with the given name carrying the channel and, for
+// the events of a single party, its index.
+func (c *SimContract) emit(name string, ch *simChannel, idx *bool) error {
+	data, err := ch.channel.ToScVal()
+	if err != nil {
+		return errors.Join(client.ErrEncoding, err)
+	}
+	if idx != nil {
+		data, err = scval.WrapVec(xdr.ScVec{data, {Type: xdr.ScValTypeScvBool, B: idx}})
+		if err != nil {
+			return errors.Join(client.ErrEncoding, err)
+		}
+	}
+	perun, fn := xdr.ScSymbol(event.AssertPerunSymbol), xdr.ScSymbol(name)
+	contractID := *c.address.ContractId
+	c.events = append(c.events, xdr.ContractEvent{
+		ContractId: &contractID,
+		Type:       xdr.ContractEventTypeContract,
+		Body: xdr.ContractEventBody{
+			V: 0,
+			V0: &xdr.ContractEventV0{
+				Topics: xdr.ScVec{{Type: xdr.ScValTypeScvSymbol, Sym: &perun}, {Type: xdr.ScValTypeScvSymbol, Sym: &fn}},
+				Data:   data,
+			},
+		},
+	})
+	return nil
+}
+
+func (c *SimContract) checkAddress(perunAddr xdr.ScAddress) error {
+	if !perunAddr.Equals(c.address) {
+		return ErrUnknownContract
+	}
+	return nil
+}
+
+func (c *SimContract) get(perunAddr xdr.ScAddress, chanID pchannel.ID) (*simChannel, error) {
+	if err := c.checkAddress(perunAddr); err != nil {
+		return nil, err
+	}
+	ch, ok := c.channels[chanID]
+	if !ok {
+		return nil, client.ErrChannelNotFound
+	}
+	return ch, nil
+}
+
+// transferShare transfers the balances of the party in all Stellar assets of
+// the state.
+func (c *SimContract) transferShare(state *pchannel.State, idx pchannel.Index, from, to *xdr.ScAddress) error {
+	for i, asset := range state.Assets {
+		stellarAsset, ok := asset.(*types.StellarAsset)
+		if !ok {
+			continue
+		}
+		token, err := stellarAsset.MakeScAddress()
+		if err != nil {
+			return err
+		}
+		if err := c.transfer(token, from, to, state.Balances[i][idx]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transfer moves the amount of the token between two accounts. A nil account
+// mints or burns the amount.
+func (c *SimContract) transfer(token xdr.ScAddress, from, to *xdr.ScAddress, amount *big.Int) error {
+	if from != nil {
+		key, err := balanceKey(token, *from)
+		if err != nil {
+			return err
+		}
+		bal, ok := c.balances[key]
+		if !ok || bal.Cmp(amount) < 0 {
+			return ErrInsufficientBalance
+		}
+		bal.Sub(bal, amount)
+	}
+	if to != nil {
+		key, err := balanceKey(token, *to)
+		if err != nil {
+			return err
+		}
+		if _, ok := c.balances[key]; !ok {
+			c.balances[key] = new(big.Int)
+		}
+		c.balances[key].Add(c.balances[key], amount)
+	}
+	return nil
+}
+
+func balanceKey(token, account xdr.ScAddress) (simBalanceKey, error) {
+	tokenKey, err := token.String()
+	if err != nil {
+		return simBalanceKey{}, err
+	}
+	accountKey, err := account.String()
+	if err != nil {
+		return simBalanceKey{}, err
+	}
+	return simBalanceKey{token: tokenKey, account: accountKey}, nil
+}
+
+// party returns the Stellar address of the party with the given index.
+func (ch *simChannel) party(idx pchannel.Index) xdr.ScAddress {
+	if idx == 0 {
+		return ch.channel.Params.A.StellarAddr
+	}
+	return ch.channel.Params.B.StellarAddr
+}
+
+// update replaces the state of the channel with a newer state signed by both
+// parties that preserves the funds of the channel.
+func (ch *simChannel) update(state *pchannel.State, sigs []pwallet.Sig) error {
+	if state.Version < ch.state.Version {
+		return client.ErrInvalidVersionNumber
+	}
+	if len(sigs) != len(ch.params.Parts) {
+		return client.ErrInvalidSignature
+	}
+	for i, part := range ch.params.Parts {
+		ok, err := pchannel.Verify(part[wtypes.StellarBackendID], state, sigs[i])
+		if err != nil || !ok {
+			return client.ErrInvalidSignature
+		}
+	}
+	if err := pchannel.AssertAssetsEqual(state.Assets, ch.state.Assets); err != nil {
+		return client.ErrInvalidStateTransition
+	}
+	sums, prevSums := state.Balances.Sum(), ch.state.Balances.Sum()
+	for i := range sums {
+		if sums[i].Cmp(prevSums[i]) != 0 {
+			return client.ErrInvalidStateTransition
+		}
+	}
+	wireState, err := wire.MakeState(*state)
+	if err != nil {
+		return errors.Join(client.ErrEncoding, err)
+	}
+	ch.state = state.Clone()
+	ch.channel.State = wireState
+	return nil
+}
+
+func partyIndex(idx bool) pchannel.Index {
+	if idx {
+		return 1
+	}
+	return 0
+}
+
+// SimInvoker invokes a SimContract on behalf of an account. It implements
+// client.Invoker and client.EventSource.
+type SimInvoker struct {
+	contract *SimContract
+	account  xdr.ScAddress
+}
+
+var (
+	_ client.Invoker     = (*SimInvoker)(nil)
+	_ client.EventSource = (*SimInvoker)(nil)
+)
+
+// Open calls open on the simulated contract.
+func (s *SimInvoker) Open(_ context.Context, perunAddr xdr.ScAddress, params *pchannel.Params, state *pchannel.State) error {
+	s.contract.mu.Lock()
+	defer s.contract.mu.Unlock()
+	return s.contract.open(perunAddr, params, state)
+}
+
+// Abort calls abort_funding on the simulated contract.
+func (s *SimInvoker) Abort(_ context.Context, perunAddr xdr.ScAddress, state *pchannel.State) error {
+	s.contract.mu.Lock()
+	defer s.contract.mu.Unlock()
+	return s.contract.abortFunding(perunAddr, state.ID)
+}
+
+// Fund calls fund on the simulated contract.
+func (s *SimInvoker) Fund(_ context.Context, perunAddr xdr.ScAddress, chanID pchannel.ID, funderIdx bool) error {
+	s.contract.mu.Lock()
+	defer s.contract.mu.Unlock()
+	return s.contract.fund(s.account, perunAddr, chanID, funderIdx)
+}
+
+// Dispute calls dispute on the simulated contract.
+func (s *SimInvoker) Dispute(_ context.Context, perunAddr xdr.ScAddress, state *pchannel.State, sigs []pwallet.Sig) error {
+	s.contract.mu.Lock()
+	defer s.contract.mu.Unlock()
+	return s.contract.dispute(perunAddr, state, sigs)
+}
+
+// Close calls close on the simulated contract.
+func (s *SimInvoker) Close(_ context.Context, perunAddr xdr.ScAddress, state *pchannel.State, sigs []pwallet.Sig) error {
+	s.contract.mu.Lock()
+	defer s.contract.mu.Unlock()
+	return s.contract.close(perunAddr, state, sigs)
+}
+
+// ForceClose calls force_close on the simulated contract.
+func (s *SimInvoker) ForceClose(_ context.Context, perunAddr xdr.ScAddress, chanID pchannel.ID) error {
+	s.contract.mu.Lock()
+	defer s.contract.mu.Unlock()
+	return s.contract.forceClose(perunAddr, chanID)
+}
+
+// Withdraw calls withdraw on the simulated contract.
+func (s *SimInvoker) Withdraw(_ context.Context, perunAddr xdr.ScAddress, req pchannel.AdjudicatorReq, withdrawerIdx bool, oneWithdrawer bool) error {
+	s.contract.mu.Lock()
+	defer s.contract.mu.Unlock()
+	return s.contract.withdraw(s.account, perunAddr, req.Tx.State.ID, withdrawerIdx, oneWithdrawer)
+}
+
+// GetChannelInfo calls get_channel on the simulated contract.
+func (s *SimInvoker) GetChannelInfo(_ context.Context, perunAddr xdr.ScAddress, chanID pchannel.ID) (wire.Channel, error) {
+	s.contract.mu.Lock()
+	defer s.contract.mu.Unlock()
+	return s.contract.getChannel(perunAddr, chanID)
+}
+
+// TokenBalance returns the balance of the invoking account in the given token.
+func (s *SimInvoker) TokenBalance(_ context.Context, token xdr.ScAddress) (*big.Int, error) {
+	return s.contract.Balance(token, s.account)
+}
+
+// CheckTrustlines returns nil, as the simulated tokens need no trustlines.
+func (s *SimInvoker) CheckTrustlines(context.Context, []pchannel.Asset) error {
+	return nil
+}
+
+// LatestEventCursor returns a cursor pointing behind the emitted events.
+func (s *SimInvoker) LatestEventCursor(context.Context) (client.EventCursor, error) {
+	s.contract.mu.Lock()
+	defer s.contract.mu.Unlock()
+	return client.EventCursor{Token: strconv.Itoa(s.contract.firstEvent + len(s.contract.events))}, nil
+}
+
+// PerunEvents returns the events emitted after the cursor. If they were
+// pruned, client.ErrEventsPruned is returned.
+func (s *SimInvoker) PerunEvents(_ context.Context, perunAddr xdr.ScAddress, cursor client.EventCursor,
+) ([]xdr.ContractEvent, client.EventCursor, error) {
+	s.contract.mu.Lock()
+	defer s.contract.mu.Unlock()
+	if err := s.contract.checkAddress(perunAddr); err != nil {
+		return nil, cursor, err
+	}
+	next, err := strconv.Atoi(cursor.Token)
+	if err != nil {
+		return nil, cursor, fmt.Errorf("invalid cursor %q: %w", cursor.Token, err)
+	}
+	if next < s.contract.firstEvent {
+		return nil, cursor, client.ErrEventsPruned
+	}
+	evs := append([]xdr.ContractEvent(nil), s.contract.events[next-s.contract.firstEvent:]...)
+	end := s.contract.firstEvent + len(s.contract.events)
+	return evs, client.EventCursor{Token: strconv.Itoa(end)}, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+	pchannel "perun.network/go-perun/channel"
+	ptest "perun.network/go-perun/channel/test"
+	pwallet "perun.network/go-perun/wallet"
+	pkgtest "polycry.pt/poly-go/test"
+
+	"perun.network/perun-stellar-backend/channel"
+	"perun.network/perun-stellar-backend/channel/types"
+	"perun.network/perun-stellar-backend/wallet"
+	"perun.network/perun-stellar-backend/wire"
+)
+
+const (
+	// SimPollingInterval is the polling interval of the funders, adjudicators
+	// and subscriptions of a SimSetup.
+	SimPollingInterval = 10 * time.Millisecond
+	// SimChallengeDuration is the challenge duration of channels created by
+	// SimSetup.NewParamsAndState, in seconds.
+	SimChallengeDuration = 60
+)
+
+// SimSetup is a setup of two participants whose funders and adjudicators
+// use a SimContract instead of a Stellar network.
+type SimSetup struct {
+	Contract *SimContract
+	Accs     []*wallet.Account
+	// Addrs are the Stellar addresses of the participants.
+	Addrs   []xdr.ScAddress
+	Assets  []pchannel.Asset
+	Funders []*channel.Funder
+	Adjs    []*channel.Adjudicator
+	rng     *rand.Rand
+}
+
+// NewSimSetup creates a new SimSetup with two Stellar assets, of which each
+// participant owns initTokenBalance.
+func NewSimSetup(t *testing.T, oneWithdrawer bool) *SimSetup {
+	t.Helper()
+	rng := pkgtest.Prng(t)
+	s := &SimSetup{Contract: NewSimContract(), rng: rng}
+
+	assets := []*types.StellarAsset{NewRandomStellarAsset(), NewRandomStellarAsset()}
+	tokens := make([]xdr.ScAddress, len(assets))
+	for i, asset := range assets {
+		token, err := asset.MakeScAddress()
+		require.NoError(t, err)
+		tokens[i] = token
+		s.Assets = append(s.Assets, asset)
+	}
+	tokenVector, err := MakeCrossAssetVector(tokens)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		acc, kp, err := wallet.NewRandomAccount(rng)
+		require.NoError(t, err)
+		addr, err := types.MakeAccountAddress(kp)
+		require.NoError(t, err)
+		for _, token := range tokens {
+			require.NoError(t, s.Contract.Mint(token, addr, new(big.Int).SetUint64(initTokenBalance)))
+		}
+
+		invoker := s.Contract.Invoker(addr)
+		funder := channel.NewFunder(acc, invoker, s.Contract.Address(), tokenVector)
+		funder.SetPollingInterval(SimPollingInterval)
+		adj := channel.NewAdjudicator(acc, invoker, s.Contract.Address(), tokenVector, oneWithdrawer)
+		adj.SetSubscriptionPollingInterval(SimPollingInterval)
+
+		s.Accs = append(s.Accs, acc)
+		s.Addrs = append(s.Addrs, addr)
+		s.Funders = append(s.Funders, funder)
+		s.Adjs = append(s.Adjs, adj)
+	}
+	return s
+}
+
+// NewParamsAndState creates channel params and an initial state of the
+// participants over the assets of the setup. Each call creates a new channel.
+func (s *SimSetup) NewParamsAndState(t *testing.T) (*pchannel.Params, *pchannel.State) {
+	t.Helper()
+	addrs := make([]pwallet.Address, len(s.Accs))
+	for i, acc := range s.Accs {
+		addrs[i] = acc.Address()
+	}
+	return newParamsWithAddressStateWithAsset(s.rng, addrs, s.Assets, ptest.WithChallengeDuration(SimChallengeDuration))
+}
+
+// Fund funds the channel by both participants.
+func (s *SimSetup) Fund(ctx context.Context, params *pchannel.Params, state *pchannel.State) error {
+	reqs := make([]*pchannel.FundingReq, len(s.Funders))
+	for i := range s.Funders {
+		reqs[i] = pchannel.NewFundingReq(params, state, pchannel.Index(i), state.Balances)
+	}
+	return FundAll(ctx, s.Funders, reqs)
+}
+
+// Sign signs the state by both participants.
+func (s *SimSetup) Sign(t *testing.T, state *pchannel.State) []pwallet.Sig {
+	t.Helper()
+	sigs := make([]pwallet.Sig, len(s.Accs))
+	for i, acc := range s.Accs {
+		sig, err := channel.Backend.Sign(acc, state)
+		require.NoError(t, err)
+		sigs[i] = sig
+	}
+	return sigs
+}
+
+// Balance returns the balance of the participant in the asset.
+func (s *SimSetup) Balance(t *testing.T, part int, asset pchannel.Asset) *big.Int {
+	t.Helper()
+	token, err := types.MustStellarAsset(asset).MakeScAddress()
+	require.NoError(t, err)
+	bal, err := s.Contract.Balance(token, s.Addrs[part])
+	require.NoError(t, err)
+	return bal
+}
+
+// Channel returns the channel as stored by the contract.
+func (s *SimSetup) Channel(id pchannel.ID) (wire.Channel, error) {
+	return s.Contract.Invoker(s.Addrs[0]).GetChannelInfo(context.Background(), s.Contract.Address(), id)
+}
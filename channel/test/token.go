@@ -15,17 +15,15 @@
 package test
 
 import (
+	"context"
 	"errors"
-	"log"
 	"math"
 	"testing"
 
-	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/require"
 
-	"perun.network/perun-stellar-backend/channel"
 	"perun.network/perun-stellar-backend/channel/types"
 	"perun.network/perun-stellar-backend/client"
 	"perun.network/perun-stellar-backend/event"
@@ -115,12 +113,12 @@ func InitTokenContract(kp *keypair.Full, contractIDAddress xdr.ScAddress, url st
 		panic(err)
 	}
 
-	txMeta, err := cb.InvokeSignedTx("initialize", initArgs, contractIDAddress)
+	txMeta, err := cb.InvokeSignedTx(context.Background(), "initialize", initArgs, contractIDAddress)
 	if err != nil {
 		return errors.New("error while invoking and processing host function: initialize" + err.Error())
 	}
 
-	_, err = event.DecodeEventsPerun(txMeta)
+	_, err = event.DecodeEventsPerun(txMeta, contractIDAddress)
 	if err != nil {
 		return err
 	}
@@ -133,7 +131,7 @@ func GetTokenName(kp *keypair.Full, contractAddress xdr.ScAddress, url string) e
 	cb := NewContractBackendFromKey(kp, nil, url)
 	TokenNameArgs := xdr.ScVec{}
 
-	_, err := cb.InvokeSignedTx("name", TokenNameArgs, contractAddress)
+	_, err := cb.InvokeSignedTx(context.Background(), "name", TokenNameArgs, contractAddress)
 	if err != nil {
 		panic(err)
 	}
@@ -181,52 +179,13 @@ func BuildTransferTokenArgs(from xdr.ScAddress, to xdr.ScAddress, amount xdr.Int
 	return GetTokenBalanceArgs, nil
 }
 
-// Deploy deploys the token contract.
+// Deploy deploys the contract code from the wasm file and returns the
+// address of the contract and the hash of its code.
 func Deploy(t *testing.T, kp *keypair.Full, contractPath string, url string) (xdr.ScAddress, xdr.Hash) {
-	deployerCB := NewContractBackendFromKey(kp, nil, url)
-	tr := deployerCB.GetTransactor()
-	hzClient := tr.GetHorizonClient()
-	deployerAccReq := horizonclient.AccountRequest{AccountID: kp.Address()}
-	deployerAcc, err := hzClient.AccountDetail(deployerAccReq)
-
-	require.NoError(t, err)
-
-	installContractOpInstall := channel.AssembleInstallContractCodeOp(kp.Address(), contractPath)
-	preFlightOp, minFeeInstall, err := client.PreflightHostFunctions(hzClient, &deployerAcc, *installContractOpInstall)
-
-	require.NoError(t, err)
-
-	txParamsInstall := client.GetBaseTransactionParamsWithFee(&deployerAcc, int64(100)+minFeeInstall, &preFlightOp) //nolint:gomnd
-	txSignedInstall, err := client.CreateSignedTransactionWithParams([]*keypair.Full{kp}, txParamsInstall, client.NETWORK_PASSPHRASE)
-	require.NoError(t, err)
-
-	_, err = hzClient.SubmitTransaction(txSignedInstall)
-	var hErr *horizonclient.Error
-	if errors.As(err, &hErr) {
-		log.Println(hErr.Problem, "fee: ", minFeeInstall)
-	}
-
+	deployer := client.NewDeployer(NewContractBackendFromKey(kp, nil, url))
+	deployment, err := deployer.DeployFile(context.Background(), contractPath, client.SaltFromString("a1"))
 	require.NoError(t, err)
-
-	createContractOp := channel.AssembleCreateContractOp(kp.Address(), contractPath, "a1", client.NETWORK_PASSPHRASE)
-	preFlightOpCreate, minFeeDeploy, err := client.PreflightHostFunctions(hzClient, &deployerAcc, *createContractOp)
-	require.NoError(t, err)
-	txParamsCreate := client.GetBaseTransactionParamsWithFee(&deployerAcc, int64(100)+minFeeDeploy, &preFlightOpCreate) //nolint:gomnd
-	txSignedCreate, err := client.CreateSignedTransactionWithParams([]*keypair.Full{kp}, txParamsCreate, client.NETWORK_PASSPHRASE)
-
-	require.NoError(t, err)
-
-	_, err = hzClient.SubmitTransaction(txSignedCreate)
-	require.NoError(t, err)
-
-	contractID := preFlightOpCreate.Ext.SorobanData.Resources.Footprint.ReadWrite[0].MustContractData().Contract.ContractId
-	contractHash := preFlightOpCreate.Ext.SorobanData.Resources.Footprint.ReadOnly[0].MustContractCode().Hash
-	contractIDAddress := xdr.ScAddress{
-		Type:       xdr.ScAddressTypeScAddressTypeContract,
-		ContractId: contractID,
-	}
-
-	return contractIDAddress, contractHash
+	return deployment.Address, deployment.WasmHash
 }
 
 // MintToken mints a token.
@@ -247,7 +206,7 @@ func MintToken(kp *keypair.Full, contractAddr xdr.ScAddress, amount uint64, reci
 	if err != nil {
 		panic(err)
 	}
-	_, err = cb.InvokeSignedTx("mint", mintTokenArgs, contractAddr)
+	_, err = cb.InvokeSignedTx(context.Background(), "mint", mintTokenArgs, contractAddr)
 	if err != nil {
 		panic(err)
 	}
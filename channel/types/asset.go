@@ -43,6 +43,8 @@ type (
 	StellarAsset struct {
 		Asset Asset
 		id    CCID
+		// classic is the classic asset wrapped by the contract, if known.
+		classic *xdr.Asset
 	}
 	// CCID is a unique identifier for a channel asset.
 	CCID struct {
@@ -18,6 +18,8 @@ import (
 	"testing"
 
 	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
 	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/require"
 
@@ -50,3 +52,42 @@ func TestMakeAccountAddress(t *testing.T) {
 
 	require.Equal(t, xdr.ScAddressTypeScAddressTypeAccount, address.Type, "Expected account address type, got %v", address.Type)
 }
+
+// TestNewStellarAssetFromClassic tests deriving the Stellar Asset Contract of classic assets.
+func TestNewStellarAssetFromClassic(t *testing.T) {
+	native, err := types.NewNativeStellarAsset(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	require.True(t, native.IsNative())
+	addr, err := native.MakeScAddress()
+	require.NoError(t, err)
+	str, err := addr.String()
+	require.NoError(t, err)
+	require.Equal(t, "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC", str)
+
+	issuer := keypair.MustRandom().Address()
+	usdc := txnbuild.CreditAsset{Code: "USDC", Issuer: issuer}
+	asset, err := types.NewStellarAssetFromClassic(usdc, network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	require.False(t, asset.IsNative())
+	classic, ok := asset.ClassicAsset()
+	require.True(t, ok)
+	require.Equal(t, "USDC:"+issuer, classic.StringCanonical())
+
+	// The classic asset is not encoded, but can be matched against the contract name.
+	data, err := asset.MarshalBinary()
+	require.NoError(t, err)
+	decoded := &types.StellarAsset{}
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	_, ok = decoded.ClassicAsset()
+	require.False(t, ok)
+	matched, err := decoded.MatchClassicAsset("USDC:"+issuer, network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	require.True(t, classic.Equals(matched))
+
+	_, err = decoded.MatchClassicAsset("EURC:"+issuer, network.TestNetworkPassphrase)
+	require.Error(t, err)
+	_, err = decoded.MatchClassicAsset("USDC:"+issuer, network.PublicNetworkPassphrase)
+	require.Error(t, err)
+	_, err = decoded.MatchClassicAsset("PerunToken", network.TestNetworkPassphrase)
+	require.Error(t, err)
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// NewStellarAssetFromClassic creates the Stellar asset of the Stellar Asset
+// Contract (SAC) wrapping the classic asset on the network with the given
+// passphrase. The contract must be deployed before it can be used in a
+// channel, see client.Deployer.DeployStellarAssetContract.
+func NewStellarAssetFromClassic(asset txnbuild.Asset, passphrase string) (*StellarAsset, error) {
+	xdrAsset, err := asset.ToXDR()
+	if err != nil {
+		return nil, errors.Join(errors.New("invalid classic asset"), err)
+	}
+	return newStellarAssetFromXdrClassic(xdrAsset, passphrase)
+}
+
+// NewNativeStellarAsset creates the Stellar asset of the Stellar Asset
+// Contract of native XLM on the network with the given passphrase.
+func NewNativeStellarAsset(passphrase string) (*StellarAsset, error) {
+	return NewStellarAssetFromClassic(txnbuild.NativeAsset{}, passphrase)
+}
+
+func newStellarAssetFromXdrClassic(asset xdr.Asset, passphrase string) (*StellarAsset, error) {
+	contractID, err := asset.ContractID(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	s := NewStellarAsset(contractID)
+	s.classic = &asset
+	return s, nil
+}
+
+// ClassicAsset returns the classic asset wrapped by the contract of the
+// asset, if the asset was created from a classic asset. Assets decoded from
+// their binary representation only carry the contract ID, use
+// MatchClassicAsset to recover the classic asset from the name of the
+// contract.
+func (s StellarAsset) ClassicAsset() (xdr.Asset, bool) {
+	if s.classic == nil {
+		return xdr.Asset{}, false
+	}
+	return *s.classic, true
+}
+
+// IsNative reports whether the asset is known to be native XLM.
+func (s StellarAsset) IsNative() bool {
+	return s.classic != nil && s.classic.Type == xdr.AssetTypeAssetTypeNative
+}
+
+// MatchClassicAsset parses the name of a Stellar Asset Contract, which is
+// "native" or "CODE:ISSUER", and checks that the contract of the asset is the
+// Stellar Asset Contract of the parsed classic asset, which is returned.
+func (s StellarAsset) MatchClassicAsset(name, passphrase string) (xdr.Asset, error) {
+	assets, err := xdr.BuildAssets(name)
+	if err != nil {
+		return xdr.Asset{}, fmt.Errorf("%q is not a classic asset: %w", name, err)
+	}
+	if len(assets) != 1 {
+		return xdr.Asset{}, fmt.Errorf("%q is not a single classic asset", name)
+	}
+	sac, err := newStellarAssetFromXdrClassic(assets[0], passphrase)
+	if err != nil {
+		return xdr.Asset{}, err
+	}
+	if sac.Asset.contractID != s.Asset.contractID {
+		return xdr.Asset{}, fmt.Errorf("contract %s is not the Stellar Asset Contract of %s", s.Asset.contractID.HexString(), name)
+	}
+	return assets[0], nil
+}
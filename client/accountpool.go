@@ -0,0 +1,126 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// ErrNoSignedSender is returned when an AccountPool is used with a sender
+// that cannot submit transactions signed by a channel account.
+var ErrNoSignedSender = errors.New("sender does not support pre-signed transactions")
+
+// AccountPool is a pool of channel accounts. The channel accounts act as
+// transaction sources and pay the fees, while the participant only
+// authorizes the contract invocations through Soroban authorization entries.
+// Using several channel accounts lets a single participant submit
+// transactions in parallel.
+type AccountPool struct {
+	accounts []*poolAccount
+	next     atomic.Uint64
+}
+
+type poolAccount struct {
+	signer TxSigner
+	seq    *SequenceManager
+}
+
+// NewAccountPool creates a new AccountPool from the keypairs of the channel
+// accounts. The accounts must exist on the ledger.
+func NewAccountPool(kps ...*keypair.Full) *AccountPool {
+	accounts := make([]*poolAccount, len(kps))
+	for i, kp := range kps {
+		accounts[i] = &poolAccount{signer: NewKeypairSigner(kp)}
+	}
+	return &AccountPool{accounts: accounts}
+}
+
+// Size returns the number of channel accounts in the pool.
+func (p *AccountPool) Size() int {
+	return len(p.accounts)
+}
+
+// Addresses returns the addresses of the channel accounts.
+func (p *AccountPool) Addresses() []string {
+	addresses := make([]string, len(p.accounts))
+	for i, acc := range p.accounts {
+		addresses[i] = acc.signer.Address()
+	}
+	return addresses
+}
+
+// init sets up the sequence managers of the channel accounts.
+func (p *AccountPool) init(load func(ctx context.Context, address string) (txnbuild.Account, error)) {
+	for _, acc := range p.accounts {
+		address := acc.signer.Address()
+		acc.seq = NewSequenceManager(func(ctx context.Context) (txnbuild.Account, error) {
+			return load(ctx, address)
+		})
+	}
+}
+
+// pick returns the next channel account in round-robin order.
+func (p *AccountPool) pick() (*poolAccount, error) {
+	if len(p.accounts) == 0 {
+		return nil, errors.New("account pool is empty")
+	}
+	i := p.next.Add(1) - 1
+	return p.accounts[i%uint64(len(p.accounts))], nil
+}
+
+// invokeFromPool invokes a contract function with a channel account of the
+// pool as transaction source. The participant authorizes the invocation by
+// signing the authorization entries returned by the simulation.
+func (c *ContractBackend) invokeFromPool(ctx context.Context, fname string, callTxArgs xdr.ScVec, contractAddr xdr.ScAddress) (xdr.TransactionMeta, error) {
+	source, err := c.tr.pool.pick()
+	if err != nil {
+		return xdr.TransactionMeta{}, err
+	}
+	send, err := c.poolSend(source)
+	if err != nil {
+		return xdr.TransactionMeta{}, err
+	}
+	return c.invokeAuthorized(ctx, source.seq, send, fname, callTxArgs, contractAddr, c.AuthorizeEntries)
+}
+
+// poolSend returns a signSendFunc that signs with the channel account and
+// submits the transaction, wrapped into a fee-bump if a sponsor is set.
+func (c *ContractBackend) poolSend(source *poolAccount) (signSendFunc, error) {
+	if c.tr.sponsor != nil {
+		return c.sponsoredSend(c.signWith(source.signer))
+	}
+	sender, ok := c.tr.sender.(SignedSender)
+	if !ok {
+		return nil, ErrNoSignedSender
+	}
+	return func(ctx context.Context, txUnsigned txnbuild.Transaction) (string, xdr.TransactionMeta, error) {
+		tx, err := signTx(ctx, source.signer, c.tr.network.Passphrase, &txUnsigned)
+		if err != nil {
+			return "", xdr.TransactionMeta{}, err
+		}
+		hash, err := tx.HashHex(c.tr.network.Passphrase)
+		if err != nil {
+			return "", xdr.TransactionMeta{}, err
+		}
+		txMeta, err := sender.SendSignedTx(ctx, tx)
+		return hash, txMeta, err
+	}, nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/perun-stellar-backend/client"
+)
+
+func accountScAddress(t *testing.T, kp keypair.KP) xdr.ScAddress {
+	accountID, err := xdr.AddressToAccountId(kp.Address())
+	require.NoError(t, err)
+	addr, err := xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeAccount, accountID)
+	require.NoError(t, err)
+	return addr
+}
+
+func addressAuthEntry(t *testing.T, kp keypair.KP, contract xdr.ScAddress) xdr.SorobanAuthorizationEntry {
+	return xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{
+			Type: xdr.SorobanCredentialsTypeSorobanCredentialsAddress,
+			Address: &xdr.SorobanAddressCredentials{
+				Address:   accountScAddress(t, kp),
+				Nonce:     42,
+				Signature: xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+			},
+		},
+		RootInvocation: xdr.SorobanAuthorizedInvocation{
+			Function: xdr.SorobanAuthorizedFunction{
+				Type: xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+				ContractFn: &xdr.InvokeContractArgs{
+					ContractAddress: contract,
+					FunctionName:    "fund",
+					Args:            xdr.ScVec{},
+				},
+			},
+			SubInvocations: []xdr.SorobanAuthorizedInvocation{},
+		},
+	}
+}
+
+// verifyAuthEntry checks the signature of an authorization entry signed by kp.
+func verifyAuthEntry(t *testing.T, entry xdr.SorobanAuthorizationEntry, kp keypair.KP, passphrase string) {
+	creds := entry.Credentials.MustAddress()
+	preimage := xdr.HashIdPreimage{
+		Type: xdr.EnvelopeTypeEnvelopeTypeSorobanAuthorization,
+		SorobanAuthorization: &xdr.HashIdPreimageSorobanAuthorization{
+			NetworkId:                 network.ID(passphrase),
+			Nonce:                     creds.Nonce,
+			SignatureExpirationLedger: creds.SignatureExpirationLedger,
+			Invocation:                entry.RootInvocation,
+		},
+	}
+	payload, err := preimage.MarshalBinary()
+	require.NoError(t, err)
+	hash := sha256.Sum256(payload)
+
+	sigs := creds.Signature.MustVec()
+	require.Len(t, *sigs, 1)
+	sigMap := (*sigs)[0].MustMap()
+	require.Len(t, *sigMap, 2)
+	sig := (*sigMap)[1].Val.MustBytes()
+	require.NoError(t, kp.Verify(hash[:], sig))
+}
+
+func TestSignAuthEntries(t *testing.T) {
+	kp := keypair.MustRandom()
+	contract := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.Hash{1}}
+	sourceEntry := xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{Type: xdr.SorobanCredentialsTypeSorobanCredentialsSourceAccount},
+	}
+
+	signed, err := client.SignAuthEntries(context.Background(),
+		[]xdr.SorobanAuthorizationEntry{sourceEntry, addressAuthEntry(t, kp, contract)},
+		client.NewKeypairSigner(kp), network.TestNetworkPassphrase, 1000)
+	require.NoError(t, err)
+	require.Len(t, signed, 2)
+	require.Equal(t, sourceEntry, signed[0])
+	require.Equal(t, xdr.Uint32(1000), signed[1].Credentials.MustAddress().SignatureExpirationLedger)
+	verifyAuthEntry(t, signed[1], kp, network.TestNetworkPassphrase)
+
+	_, err = client.SignAuthEntries(context.Background(),
+		[]xdr.SorobanAuthorizationEntry{addressAuthEntry(t, keypair.MustRandom(), contract)},
+		client.NewKeypairSigner(kp), network.TestNetworkPassphrase, 1000)
+	require.ErrorIs(t, err, client.ErrForeignAuthEntry)
+}
+
+func TestAccountPoolRoundRobin(t *testing.T) {
+	participant := keypair.MustRandom()
+	pool := []*keypair.Full{keypair.MustRandom(), keypair.MustRandom()}
+	contract := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.Hash{1}}
+
+	rpc, url := newFakeRPC(t)
+	net := client.StandaloneNetwork()
+	net.SorobanRPCURL = url
+
+	rpc.handle("getLedgerEntries", func(params json.RawMessage) interface{} {
+		var req struct {
+			Keys []string `json:"keys"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		var key xdr.LedgerKey
+		require.NoError(t, xdr.SafeUnmarshalBase64(req.Keys[0], &key))
+		kp := keypair.MustParseAddress(key.Account.AccountId.Address())
+		return client.RPCGetLedgerEntriesResponse{
+			Entries: []client.RPCLedgerEntry{{XDR: accountEntryXdr(t, kp, 10)}},
+		}
+	})
+	rpc.handle("getLatestLedger", func(json.RawMessage) interface{} {
+		return map[string]uint32{"sequence": 7}
+	})
+	txData, err := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+	require.NoError(t, err)
+	retXdr, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	require.NoError(t, err)
+	authXdr, err := xdr.MarshalBase64(addressAuthEntry(t, participant, contract))
+	require.NoError(t, err)
+	rpc.handle("simulateTransaction", func(json.RawMessage) interface{} {
+		return client.RPCSimulateTxResponse{
+			TransactionData: txData,
+			Results:         []client.RPCSimulateHostFunctionResult{{XDR: retXdr, Auth: []string{authXdr}}},
+		}
+	})
+	var sources []string
+	rpc.handle("sendTransaction", func(params json.RawMessage) interface{} {
+		var req struct {
+			Transaction string `json:"transaction"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		generic, err := txnbuild.TransactionFromXDR(req.Transaction)
+		require.NoError(t, err)
+		tx, ok := generic.Transaction()
+		require.True(t, ok)
+		sources = append(sources, tx.SourceAccount().AccountID)
+		invoke, ok := tx.Operations()[0].(*txnbuild.InvokeHostFunction)
+		if !ok {
+			return client.RPCSendTxResponse{Status: client.TxStatusPending, Hash: "abc"}
+		}
+		auth := invoke.Auth
+		require.Len(t, auth, 1)
+		verifyAuthEntry(t, auth[0], participant, net.Passphrase)
+		require.Equal(t, xdr.Uint32(7+client.DefaultAuthValidityLedgers),
+			auth[0].Credentials.MustAddress().SignatureExpirationLedger)
+		return client.RPCSendTxResponse{Status: client.TxStatusPending, Hash: "abc"}
+	})
+	meta, err := xdr.MarshalBase64(xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{}})
+	require.NoError(t, err)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+
+	sender := client.NewRPCSender(participant, net)
+	sender.SetPolling(time.Millisecond, 5)
+	cfg := client.TransactorConfig{}
+	cfg.SetKeyPair(participant)
+	cfg.SetNetwork(net)
+	cfg.SetSender(sender)
+	cfg.SetAccountPool(client.NewAccountPool(pool...))
+	cb := client.NewContractBackend(&cfg)
+
+	for i := 0; i < 4; i++ {
+		_, err = cb.InvokeSignedTx(context.Background(), "fund", xdr.ScVec{}, contract)
+		require.NoError(t, err)
+	}
+	// Extending the TTL of channels does not need the participant's account either.
+	key, err := client.ChannelLedgerKey(contract, [32]byte{1})
+	require.NoError(t, err)
+	require.NoError(t, cb.ExtendTTL(context.Background(), []xdr.LedgerKey{key}, 1000))
+	require.Equal(t, []string{
+		pool[0].Address(), pool[1].Address(), pool[0].Address(), pool[1].Address(), pool[0].Address(),
+	}, sources)
+	// Each channel account is loaded once, afterwards sequence numbers are tracked locally.
+	require.Equal(t, 2, rpc.count("getLedgerEntries"))
+}
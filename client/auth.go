@@ -0,0 +1,163 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+
+	"perun.network/perun-stellar-backend/wire/scval"
+)
+
+// DefaultAuthValidityLedgers is the number of ledgers for which a signed
+// authorization entry stays valid.
+const DefaultAuthValidityLedgers = 100
+
+// ErrForeignAuthEntry is returned when an authorization entry has to be
+// signed by an address whose key is not available.
+var ErrForeignAuthEntry = errors.New("authorization entry requires a foreign signature")
+
+// SignAuthEntries signs all authorization entries with address credentials
+// of the signer's address. Entries with source account credentials are
+// authorized by the transaction signature and returned unchanged. An entry for
+// any other address results in ErrForeignAuthEntry.
+func SignAuthEntries(ctx context.Context, entries []xdr.SorobanAuthorizationEntry, txSigner TxSigner, passphrase string,
+	expirationLedger uint32,
+) ([]xdr.SorobanAuthorizationEntry, error) {
+	return signAuthEntries(ctx, entries, txSigner.Address(), []TxSigner{txSigner}, passphrase, expirationLedger)
+}
+
+// signAuthEntries signs all authorization entries with address credentials
+// of the account with every signer, see SignAuthEntries.
+func signAuthEntries(ctx context.Context, entries []xdr.SorobanAuthorizationEntry, account string, signers []TxSigner,
+	passphrase string, expirationLedger uint32,
+) ([]xdr.SorobanAuthorizationEntry, error) {
+	accountID, err := xdr.AddressToAccountId(account)
+	if err != nil {
+		return nil, err
+	}
+	accountAddr, err := xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeAccount, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := make([]xdr.SorobanAuthorizationEntry, len(entries))
+	for i, entry := range entries {
+		creds, ok := entry.Credentials.GetAddress()
+		if !ok {
+			signed[i] = entry
+			continue
+		}
+		if !creds.Address.Equals(accountAddr) {
+			address, _ := creds.Address.String()
+			return nil, fmt.Errorf("%w: %s", ErrForeignAuthEntry, address)
+		}
+		signed[i], err = signAuthEntry(ctx, entry, signers, passphrase, expirationLedger)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return signed, nil
+}
+
+// signAuthEntry signs an authorization entry with address credentials as
+// expected by the account authorization of the Soroban host.
+func signAuthEntry(ctx context.Context, entry xdr.SorobanAuthorizationEntry, signers []TxSigner, passphrase string,
+	expirationLedger uint32,
+) (xdr.SorobanAuthorizationEntry, error) {
+	creds := *entry.Credentials.Address
+	preimage := xdr.HashIdPreimage{
+		Type: xdr.EnvelopeTypeEnvelopeTypeSorobanAuthorization,
+		SorobanAuthorization: &xdr.HashIdPreimageSorobanAuthorization{
+			NetworkId:                 network.ID(passphrase),
+			Nonce:                     creds.Nonce,
+			SignatureExpirationLedger: xdr.Uint32(expirationLedger),
+			Invocation:                entry.RootInvocation,
+		},
+	}
+	sigs := make([]accountSignature, len(signers))
+	for i, signer := range signers {
+		sig, err := signer.SignAuthEntry(ctx, preimage)
+		if err != nil {
+			return xdr.SorobanAuthorizationEntry{}, err
+		}
+		sigs[i] = accountSignature{address: signer.Address(), sig: sig}
+	}
+
+	sigVal, err := accountSignaturesScVal(sigs)
+	if err != nil {
+		return xdr.SorobanAuthorizationEntry{}, err
+	}
+	creds.SignatureExpirationLedger = xdr.Uint32(expirationLedger)
+	creds.Signature = sigVal
+	entry.Credentials.Address = &creds
+	return entry, nil
+}
+
+// accountSignature is the ed25519 signature of a signer of a Stellar account.
+type accountSignature struct {
+	address string
+	sig     []byte
+}
+
+// accountSignaturesScVal encodes ed25519 signatures of the signers of a
+// Stellar account as vec![{public_key: bytes, signature: bytes}]. The Soroban
+// host requires the signatures to be ordered by public key.
+func accountSignaturesScVal(sigs []accountSignature) (xdr.ScVal, error) {
+	type keyedSig struct {
+		pubKey xdr.Uint256
+		sig    []byte
+	}
+	keyed := make([]keyedSig, len(sigs))
+	for i, s := range sigs {
+		accountID, err := xdr.AddressToAccountId(s.address)
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		if accountID.Ed25519 == nil {
+			return xdr.ScVal{}, errors.New("account is not an ed25519 account")
+		}
+		keyed[i] = keyedSig{pubKey: *accountID.Ed25519, sig: s.sig}
+	}
+	sort.Slice(keyed, func(i, j int) bool {
+		return bytes.Compare(keyed[i].pubKey[:], keyed[j].pubKey[:]) < 0
+	})
+
+	vec := make(xdr.ScVec, len(keyed))
+	for i, k := range keyed {
+		pubKeyVal, err := scval.WrapScBytes(k.pubKey[:])
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		sigVal, err := scval.WrapScBytes(k.sig)
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		vec[i], err = scval.WrapScMap(xdr.ScMap{
+			{Key: scval.MustWrapScSymbol("public_key"), Val: pubKeyVal},
+			{Key: scval.MustWrapScSymbol("signature"), Val: sigVal},
+		})
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+	}
+	return scval.WrapVec(vec)
+}
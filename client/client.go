@@ -25,7 +25,6 @@ import (
 	pchannel "perun.network/go-perun/channel"
 	pwallet "perun.network/go-perun/wallet"
 
-	"perun.network/perun-stellar-backend/channel/types"
 	"perun.network/perun-stellar-backend/event"
 	"perun.network/perun-stellar-backend/wire"
 )
@@ -50,12 +49,12 @@ func (c *ContractBackend) Open(ctx context.Context, perunAddr xdr.ScAddress, par
 	if err != nil {
 		return errors.New("error while building open tx")
 	}
-	txMeta, err := c.InvokeSignedTx("open", openTxArgs, perunAddr)
+	txMeta, err := c.InvokeSignedTx(ctx, "open", openTxArgs, perunAddr)
 	if err != nil {
 		return errors.Join(errors.New("error while invoking and processing host function: open"), err)
 	}
 
-	evs, err := event.DecodeEventsPerun(txMeta)
+	evs, err := event.DecodeEventsPerun(txMeta, perunAddr)
 	if err != nil {
 		return err
 	}
@@ -74,12 +73,12 @@ func (c *ContractBackend) Abort(ctx context.Context, perunAddr xdr.ScAddress, st
 	if err != nil {
 		return errors.New("error while building abort_funding tx")
 	}
-	txMeta, err := c.InvokeSignedTx("abort_funding", abortTxArgs, perunAddr)
+	txMeta, err := c.InvokeSignedTx(ctx, "abort_funding", abortTxArgs, perunAddr)
 	if err != nil {
-		return errors.New("error while invoking and processing host function: abort_funding")
+		return errors.Join(errors.New("error while invoking and processing host function: abort_funding"), err)
 	}
 
-	_, err = event.DecodeEventsPerun(txMeta)
+	_, err = event.DecodeEventsPerun(txMeta, perunAddr)
 	if err != nil {
 		return err
 	}
@@ -95,12 +94,12 @@ func (c *ContractBackend) Fund(ctx context.Context, perunAddr xdr.ScAddress, cha
 		return errors.New("error while building fund tx")
 	}
 
-	txMeta, err := c.InvokeSignedTx("fund", fundTxArgs, perunAddr)
+	txMeta, err := c.InvokeSignedTx(ctx, "fund", fundTxArgs, perunAddr)
 	if err != nil {
 		return err
 	}
 
-	evs, err := event.DecodeEventsPerun(txMeta)
+	evs, err := event.DecodeEventsPerun(txMeta, perunAddr)
 	if err != nil {
 		return err
 	}
@@ -132,17 +131,20 @@ func (c *ContractBackend) Close(ctx context.Context, perunAddr xdr.ScAddress, st
 	if err != nil {
 		return errors.New("error while building fund tx")
 	}
-	txMeta, err := c.InvokeSignedTx("close", closeTxArgs, perunAddr)
+	txMeta, err := c.InvokeSignedTx(ctx, "close", closeTxArgs, perunAddr)
 	if err != nil {
-		return errors.New("error while invoking and processing host function: close")
+		return errors.Join(errors.New("error while invoking and processing host function: close"), err)
 	}
 
-	evs, err := event.DecodeEventsPerun(txMeta)
+	evs, err := event.DecodeEventsPerun(txMeta, perunAddr)
 	if err != nil {
 		return err
 	}
 
 	err = event.AssertCloseEvent(evs)
+	if err == nil {
+		return nil
+	}
 	if err == event.ErrNoCloseEvent {
 		chanInfo, err := c.GetChannelInfo(ctx, perunAddr, state.ID)
 		if err != nil {
@@ -164,11 +166,11 @@ func (c *ContractBackend) ForceClose(ctx context.Context, perunAddr xdr.ScAddres
 	if err != nil {
 		return errors.New("error while building fund tx")
 	}
-	txMeta, err := c.InvokeSignedTx("force_close", forceCloseTxArgs, perunAddr)
+	txMeta, err := c.InvokeSignedTx(ctx, "force_close", forceCloseTxArgs, perunAddr)
 	if err != nil {
-		return errors.New("error while invoking and processing host function")
+		return errors.Join(errors.New("error while invoking and processing host function: force_close"), err)
 	}
-	evs, err := event.DecodeEventsPerun(txMeta)
+	evs, err := event.DecodeEventsPerun(txMeta, perunAddr)
 	if err != nil {
 		return err
 	}
@@ -179,12 +181,11 @@ func (c *ContractBackend) ForceClose(ctx context.Context, perunAddr xdr.ScAddres
 		if err != nil {
 			return errors.New("could not retrieve channel info")
 		}
-		if !chanInfo.Control.Disputed {
-			return errors.New("force close of a state that is not disputed")
-		}
-
 		if chanInfo.Control.Closed {
-			return errors.New("force close of a channel that is closed already")
+			return ErrForceCloseOnClosedChannel
+		}
+		if !chanInfo.Control.Disputed {
+			return ErrForceCloseOnUndisputedChannel
 		}
 	}
 
@@ -197,11 +198,11 @@ func (c *ContractBackend) Dispute(ctx context.Context, perunAddr xdr.ScAddress,
 	if err != nil {
 		return errors.Join(errors.New("error while building dispute tx"), err)
 	}
-	txMeta, err := c.InvokeSignedTx("dispute", disputeTxArgs, perunAddr)
+	txMeta, err := c.InvokeSignedTx(ctx, "dispute", disputeTxArgs, perunAddr)
 	if err != nil {
 		return errors.Join(errors.New("error while invoking and processing host function: dispute"), err)
 	}
-	evs, err := event.DecodeEventsPerun(txMeta)
+	evs, err := event.DecodeEventsPerun(txMeta, perunAddr)
 	if err != nil {
 		return err
 	}
@@ -234,41 +235,21 @@ func (c *ContractBackend) Withdraw(ctx context.Context, perunAddr xdr.ScAddress,
 	if err != nil {
 		return errors.New("error building fund tx")
 	}
-	txMeta, err := c.InvokeSignedTx("withdraw", withdrawTxArgs, perunAddr)
+	txMeta, err := c.InvokeSignedTx(ctx, "withdraw", withdrawTxArgs, perunAddr)
 	if err != nil {
-		return errors.New("error in host function: withdraw")
+		return errors.Join(errors.New("error in host function: withdraw"), err)
 	}
 	tr := c.GetTransactor()
 	clientAddress, err := tr.GetAddress()
 	if err != nil {
 		log.Println("Error while getting client address: ", err)
 	}
-	tokenAddr0, ok := req.Tx.State.Assets[0].(*types.StellarAsset)
-	bal0 := "bal0"
-	bal1 := "bal1"
-	if ok {
-		cAdd0, err := types.MakeContractAddress(tokenAddr0.Asset.ContractID())
-		if err != nil {
-			return err
-		}
-		bal0, err = c.GetBalance(cAdd0)
-		if err != nil {
-			log.Println("Error while getting balance: ", err)
-		}
-	}
-	tokenAddr1, ok := req.Tx.State.Assets[1].(*types.StellarAsset)
-	if ok {
-		cAdd1, err := types.MakeContractAddress(tokenAddr1.Asset.ContractID())
-		if err != nil {
-			return err
-		}
-		bal1, err = c.GetBalance(cAdd1)
-		if err != nil {
-			log.Println("Error while getting balance: ", err)
-		}
+	bals, err := AssetBalances(ctx, c, req.Tx.State.Assets)
+	if err != nil {
+		log.Println("Error while getting balance: ", err)
 	}
-	log.Println("Balance: ", bal0, bal1, " after withdrawing: ", clientAddress, req.Tx.State.Assets)
-	evs, err := event.DecodeEventsPerun(txMeta)
+	log.Println("Balance: ", bals, " after withdrawing: ", clientAddress, req.Tx.State.Assets)
+	evs, err := event.DecodeEventsPerun(txMeta, perunAddr)
 	if err != nil {
 		return err
 	}
@@ -292,13 +273,24 @@ func (c *ContractBackend) Withdraw(ctx context.Context, perunAddr xdr.ScAddress,
 	return event.ErrNoWithdrawEvent
 }
 
-// GetChannelInfo returns the channel info.
+// GetChannelInfo returns the channel info. The channel is read directly from
+// the ledger; if that fails, get_channel is simulated instead.
 func (c *ContractBackend) GetChannelInfo(ctx context.Context, perunAddr xdr.ScAddress, chanID pchannel.ID) (wire.Channel, error) {
+	chanInfo, err := c.GetChannelFromLedger(ctx, perunAddr, chanID)
+	if err == nil {
+		return chanInfo, nil
+	}
+	log.Println("Reading channel from ledger failed, simulating get_channel: ", err)
+	return c.getChannelInfoSimulated(ctx, perunAddr, chanID)
+}
+
+// getChannelInfoSimulated returns the channel info by simulating get_channel.
+func (c *ContractBackend) getChannelInfoSimulated(ctx context.Context, perunAddr xdr.ScAddress, chanID pchannel.ID) (wire.Channel, error) {
 	getchTxArgs, err := buildChanIDTxArgs(chanID)
 	if err != nil {
 		return wire.Channel{}, errors.New("error while building get_channel tx")
 	}
-	chanInfo, _, err := c.InvokeUnsignedTx("get_channel", getchTxArgs, perunAddr)
+	chanInfo, _, err := c.InvokeUnsignedTx(ctx, "get_channel", getchTxArgs, perunAddr)
 	if err != nil {
 		return wire.Channel{}, errors.Join(errors.New("error while processing and submitting get_channel tx"), err)
 	}
@@ -307,27 +299,9 @@ func (c *ContractBackend) GetChannelInfo(ctx context.Context, perunAddr xdr.ScAd
 }
 
 // GetBalanceUser returns the balance of the user.
-func (c *ContractBackend) GetBalanceUser(cID xdr.ScAddress) (string, error) {
-	tr := c.GetTransactor()
-	addr, err := tr.GetAddress()
-	if err != nil {
-		return "", err
-	}
-	accountID, err := xdr.AddressToAccountId(addr)
-	if err != nil {
-		return "", err
-	}
-	scAddr, err := xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeAccount, accountID)
-	if err != nil {
-		return "", err
-	}
-	TokenNameArgs, err := BuildGetTokenBalanceArgs(scAddr)
-	if err != nil {
-		return "", err
-	}
-	tx, err := c.InvokeSignedTx("balance", TokenNameArgs, cID)
-	if err != nil {
-		return "", err
-	}
-	return tx.V3.SorobanMeta.ReturnValue.String(), nil
+//
+// Deprecated: Use TokenBalance, GetBalanceUser is kept for compatibility and
+// no longer submits a transaction.
+func (c *ContractBackend) GetBalanceUser(ctx context.Context, cID xdr.ScAddress) (string, error) {
+	return c.GetBalance(ctx, cID)
 }
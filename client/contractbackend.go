@@ -1,10 +1,10 @@
 package client
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"sync"
 
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/keypair"
@@ -22,16 +22,24 @@ const stellarDefaultChainID = 2
 
 // Sender is an interface for sending transactions.
 type Sender interface {
-	SignSendTx(txnbuild.Transaction) (xdr.TransactionMeta, error)
+	SignSendTx(context.Context, txnbuild.Transaction) (xdr.TransactionMeta, error)
 	SetHzClient(*horizonclient.Client)
 }
 
+// SignedSender is implemented by senders that can submit transactions signed
+// by someone else, e.g. by a channel account of an AccountPool.
+type SignedSender interface {
+	SendSignedTx(context.Context, *txnbuild.Transaction) (xdr.TransactionMeta, error)
+}
+
+var _ SignedSender = (*TxSender)(nil)
+
 // ContractBackend is a struct that implements the ContractBackend interface.
+// Signed invocations may be issued concurrently, sequence numbers are handed
+// out by the SequenceManager of the transactor.
 type ContractBackend struct {
-	Invoker
 	tr      StellarSigner
 	chainID int
-	cbMutex sync.Mutex
 }
 
 // NewContractBackend creates a new ContractBackend.
@@ -40,31 +48,70 @@ func NewContractBackend(trConfig *TransactorConfig) *ContractBackend {
 	return &ContractBackend{
 		tr:      *transactor,
 		chainID: stellarDefaultChainID,
-		cbMutex: sync.Mutex{},
 	}
 }
 
 // StellarSigner is a struct that implements the Transactor interface for Stellar.
 type StellarSigner struct {
-	keyPair     *keypair.Full
+	signer      TxSigner
+	coSigners   []TxSigner
+	collector   SignatureCollector
 	participant *types.Participant
 	account     *wallet.Account
 	hzClient    *horizonclient.Client
 	sender      Sender
+	network     NetworkConfig
+	seq         *SequenceManager
+	pool        *AccountPool
+	relayer     Relayer
+	sponsor     FeeSponsor
+	fees        FeeStrategy
+	retry       RetryPolicy
 }
 
 // TransactorConfig is a struct that contains the configuration for the Transactor.
 type TransactorConfig struct {
-	keyPair     *keypair.Full
+	signer      TxSigner
+	coSigners   []TxSigner
+	collector   SignatureCollector
 	participant *types.Participant
 	account     *wallet.Account
 	sender      Sender
-	horizonURL  string
+	hzNetwork   NetworkConfig
+	network     NetworkConfig
+	pool        *AccountPool
+	relayer     Relayer
+	sponsor     FeeSponsor
+	fees        FeeStrategy
+	retry       *RetryPolicy
 }
 
-// SetKeyPair sets the keypair of the TransactorConfig.
+// SetKeyPair sets the keypair of the TransactorConfig. The keypair is used
+// as an in-memory TxSigner.
 func (tc *TransactorConfig) SetKeyPair(kp *keypair.Full) {
-	tc.keyPair = kp
+	tc.signer = NewKeypairSigner(kp)
+}
+
+// SetSigner sets the TxSigner of the TransactorConfig, which signs
+// transactions and authorization entries on behalf of the participant.
+func (tc *TransactorConfig) SetSigner(signer TxSigner) {
+	tc.signer = signer
+}
+
+// SetCoSigners sets further signers of the participant's account, for
+// accounts whose thresholds require several signatures. Transactions are
+// only submitted once their signatures meet the threshold of the account.
+// The co-signers also sign the authorization entries of the account, e.g.
+// when invoking from an AccountPool or through a Relayer.
+func (tc *TransactorConfig) SetCoSigners(signers ...TxSigner) {
+	tc.coSigners = signers
+}
+
+// SetSignatureCollector sets a callback collecting the signatures that are
+// missing after signing with the signer and the co-signers. The sender must
+// implement SignedSender.
+func (tc *TransactorConfig) SetSignatureCollector(collector SignatureCollector) {
+	tc.collector = collector
 }
 
 // SetParticipant sets the participant of the TransactorConfig.
@@ -82,27 +129,94 @@ func (tc *TransactorConfig) SetSender(sender Sender) {
 	tc.sender = sender
 }
 
-// SetHorizonURL sets the horizon URL of the TransactorConfig.
-func (tc *TransactorConfig) SetHorizonURL(url string) {
-	tc.horizonURL = url
+// SetHorizonURL sets the network of the TransactorConfig to the preset with
+// the given horizon URL, see NetworkFromHorizonURL. It fails if the URL does
+// not belong to a preset, use SetNetwork for other networks.
+func (tc *TransactorConfig) SetHorizonURL(url string) error {
+	network, err := NetworkFromHorizonURL(url)
+	if err != nil {
+		return err
+	}
+	tc.hzNetwork = network
+	return nil
+}
+
+// SetNetwork sets the network configuration of the TransactorConfig. It takes
+// precedence over the URL set with SetHorizonURL.
+func (tc *TransactorConfig) SetNetwork(network NetworkConfig) {
+	tc.network = network
+}
+
+// SetAccountPool sets a pool of channel accounts that are used as transaction
+// sources instead of the participant's account. The sender must implement
+// SignedSender and the signer of the participant must be set.
+func (tc *TransactorConfig) SetAccountPool(pool *AccountPool) {
+	tc.pool = pool
+}
+
+// SetRelayer sets a relayer that submits all contract invocations on behalf
+// of the participant, who only signs the authorization entries. It takes
+// precedence over an AccountPool.
+func (tc *TransactorConfig) SetRelayer(relayer Relayer) {
+	tc.relayer = relayer
+}
+
+// SetFeeSponsor sets a sponsor that pays the transaction fees. The
+// participant signs the inner transaction, the sponsor wraps it into a
+// fee-bump transaction. The sender must implement FeeBumpSender.
+func (tc *TransactorConfig) SetFeeSponsor(sponsor FeeSponsor) {
+	tc.sponsor = sponsor
+}
+
+// SetFeeStrategy sets the strategy choosing the fee of every contract call.
+// By default, a FixedFeeStrategy with DefaultInclusionFee and no resource fee
+// margin is used.
+func (tc *TransactorConfig) SetFeeStrategy(fees FeeStrategy) {
+	tc.fees = fees
+}
+
+// SetRetryPolicy sets the policy for resubmitting transactions. By default,
+// DefaultRetryPolicy is used.
+func (tc *TransactorConfig) SetRetryPolicy(policy RetryPolicy) {
+	tc.retry = &policy
 }
 
 // NewTransactor creates a new Transactor using the transactor configuration.
 func NewTransactor(cfg TransactorConfig) *StellarSigner {
 	st := &StellarSigner{}
 
+	switch {
+	case !cfg.network.IsZero():
+		st.network = cfg.network
+	case !cfg.hzNetwork.IsZero():
+		st.network = cfg.hzNetwork
+	default:
+		st.network = StandaloneNetwork()
+	}
+
 	if cfg.sender != nil {
 		st.sender = cfg.sender
 	} else {
 		st.sender = &TxSender{}
 	}
+	switch sender := st.sender.(type) {
+	case *TxSender:
+		sender.network = st.network
+	case *RPCSender:
+		sender.network = st.network
+	}
 
-	if cfg.keyPair != nil {
-		st.keyPair = cfg.keyPair
-		if txSender, ok := st.sender.(*TxSender); ok {
-			txSender.kp = st.keyPair
+	if cfg.signer != nil {
+		st.signer = cfg.signer
+		switch sender := st.sender.(type) {
+		case *TxSender:
+			sender.signer = st.signer
+		case *RPCSender:
+			sender.signer = st.signer
 		}
 	}
+	st.coSigners = cfg.coSigners
+	st.collector = cfg.collector
 	if cfg.participant != nil {
 		st.participant = cfg.participant
 	}
@@ -110,10 +224,22 @@ func NewTransactor(cfg TransactorConfig) *StellarSigner {
 		st.account = cfg.account
 	}
 
-	if cfg.horizonURL != "" {
-		st.hzClient = NewHorizonClient(cfg.horizonURL)
-	} else {
-		st.hzClient = NewHorizonClient(HorizonURL)
+	st.hzClient = st.network.NewHorizonClient()
+	st.sender.SetHzClient(st.hzClient)
+	st.seq = NewSequenceManager(st.loadAccount)
+	st.relayer = cfg.relayer
+	st.sponsor = cfg.sponsor
+	st.fees = cfg.fees
+	if st.fees == nil {
+		st.fees = NewFixedFeeStrategy(DefaultInclusionFee, 0)
+	}
+	st.retry = DefaultRetryPolicy()
+	if cfg.retry != nil {
+		st.retry = *cfg.retry
+	}
+	if cfg.pool != nil {
+		st.pool = cfg.pool
+		st.pool.init(st.loadAccountFor)
 	}
 
 	return st
@@ -124,50 +250,74 @@ func (c *ContractBackend) GetTransactor() *StellarSigner {
 	return &c.tr
 }
 
-// GetBalance returns the balance of the contract.
-func (c *ContractBackend) GetBalance(cID xdr.ScAddress) (string, error) {
-	tr := c.GetTransactor()
-	add, err := tr.GetAddress()
-	if err != nil {
-		return "", err
-	}
-	accountID, err := xdr.AddressToAccountId(add)
-	if err != nil {
-		return "", err
-	}
-	scAdd, err := xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeAccount, accountID)
-	if err != nil {
-		return "", err
-	}
-	TokenNameArgs, err := BuildGetTokenBalanceArgs(scAdd)
-	if err != nil {
-		return "", err
-	}
-	_, bal, err := c.InvokeUnsignedTx("balance", TokenNameArgs, cID)
+// GetBalance returns the balance of the participant's account in the given
+// token as a decimal string. Use TokenBalance for a *big.Int.
+func (c *ContractBackend) GetBalance(ctx context.Context, cID xdr.ScAddress) (string, error) {
+	bal, err := c.TokenBalance(ctx, cID)
 	if err != nil {
 		return "", err
 	}
-	return bal, nil
+	return bal.String(), nil
 }
 
 // GetHorizonAccount returns the horizon account of the StellarSigner.
-func (st *StellarSigner) GetHorizonAccount() (horizon.Account, error) {
+func (st *StellarSigner) GetHorizonAccount(ctx context.Context) (horizon.Account, error) {
 	hzAddress, err := st.GetAddress()
 	if err != nil {
 		return horizon.Account{}, err
 	}
-	accountReq := horizonclient.AccountRequest{AccountID: hzAddress}
-	hzAccount, err := st.hzClient.AccountDetail(accountReq)
+	return st.getHorizonAccount(ctx, hzAddress)
+}
+
+func (st *StellarSigner) getHorizonAccount(ctx context.Context, address string) (horizon.Account, error) {
+	accountReq := horizonclient.AccountRequest{AccountID: address}
+	hzAccount, err := HorizonWithContext(ctx, st.hzClient).AccountDetail(accountReq)
 	if err != nil {
 		return hzAccount, err
 	}
 	return hzAccount, nil
 }
 
+// loadAccount loads the source account of the StellarSigner.
+func (st *StellarSigner) loadAccount(ctx context.Context) (txnbuild.Account, error) {
+	address, err := st.GetAddress()
+	if err != nil {
+		return nil, err
+	}
+	return st.loadAccountFor(ctx, address)
+}
+
+// loadAccountFor loads the given account, either through the sender if it is
+// an AccountLoader or from Horizon.
+func (st *StellarSigner) loadAccountFor(ctx context.Context, address string) (txnbuild.Account, error) {
+	if loader, ok := st.sender.(AccountLoader); ok {
+		return loader.LoadAccount(ctx, address)
+	}
+	hzAcc, err := st.getHorizonAccount(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	return &hzAcc, nil
+}
+
+// GetSequenceManager returns the sequence manager of the source account.
+func (st *StellarSigner) GetSequenceManager() *SequenceManager {
+	return st.seq
+}
+
+// syncHorizonClient returns the horizon client soroban-rpc has to be synced
+// with before simulating, or nil if the sender does not use Horizon.
+func (st *StellarSigner) syncHorizonClient() *horizonclient.Client {
+	if _, ok := st.sender.(AccountLoader); ok {
+		return nil
+	}
+	return st.hzClient
+}
+
 // GetAddress returns the address of the StellarSigner.
 func (st *StellarSigner) GetAddress() (string, error) {
-	if st.keyPair != nil {
-		return st.keyPair.Address(), nil
+	if st.signer != nil {
+		return st.signer.Address(), nil
 	}
 	if st.account != nil {
 		return (*st.account).Address().String(), nil
@@ -184,23 +334,24 @@ func (st *StellarSigner) GetHorizonClient() *horizonclient.Client {
 	return st.hzClient
 }
 
-// InvokeUnsignedTx invokes an unsigned transaction.
-func (c *ContractBackend) InvokeUnsignedTx(fname string, callTxArgs xdr.ScVec, contractAddr xdr.ScAddress) (wire.Channel, string, error) { // xdr.TransactionMeta, error
-	c.cbMutex.Lock()
-	defer c.cbMutex.Unlock()
+// GetNetwork returns the network configuration of the StellarSigner.
+func (st *StellarSigner) GetNetwork() NetworkConfig {
+	return st.network
+}
+
+// InvokeUnsignedTx invokes an unsigned transaction. It only simulates the
+// invocation and does not reserve a sequence number.
+func (c *ContractBackend) InvokeUnsignedTx(ctx context.Context, fname string, callTxArgs xdr.ScVec, contractAddr xdr.ScAddress) (wire.Channel, string, error) { // xdr.TransactionMeta, error
 	fnameXdr := xdr.ScSymbol(fname)
-	hzAcc, err := c.tr.GetHorizonAccount()
+	acc, err := c.tr.seq.Account(ctx)
 	if err != nil {
 		return wire.Channel{}, "", err
 	}
 
-	hzClient := c.tr.GetHorizonClient()
-
-	c.tr.sender.SetHzClient(hzClient)
 	chanInf := fname == "get_channel"
 
-	invokeHostFunctionOp := BuildContractCallOp(hzAcc, fnameXdr, callTxArgs, contractAddr)
-	chanInfo, bal, _, _, err := PreflightHostFunctionsResult(hzClient, &hzAcc, *invokeHostFunctionOp, chanInf)
+	invokeHostFunctionOp := BuildContractCallOp(acc, fnameXdr, callTxArgs, contractAddr)
+	chanInfo, bal, _, _, err := PreflightHostFunctionsResult(ctx, c.tr.syncHorizonClient(), c.tr.network, acc, *invokeHostFunctionOp, chanInf)
 	if err != nil {
 		return wire.Channel{}, "", err
 	}
@@ -208,36 +359,100 @@ func (c *ContractBackend) InvokeUnsignedTx(fname string, callTxArgs xdr.ScVec, c
 	return chanInfo, bal, nil
 }
 
-// InvokeSignedTx invokes a signed transaction.
-func (c *ContractBackend) InvokeSignedTx(fname string, callTxArgs xdr.ScVec, contractAddr xdr.ScAddress) (xdr.TransactionMeta, error) {
-	c.cbMutex.Lock()
-	defer c.cbMutex.Unlock()
-	fnameXdr := xdr.ScSymbol(fname)
-	hzAcc, err := c.tr.GetHorizonAccount()
+// SimulateCall simulates a call of the contract function and returns its
+// result. Nothing is submitted.
+func (c *ContractBackend) SimulateCall(ctx context.Context, fname string, args xdr.ScVec, contract xdr.ScAddress) (xdr.ScVal, error) {
+	acc, err := c.tr.seq.Account(ctx)
 	if err != nil {
-		return xdr.TransactionMeta{}, errors.Join(errors.New("failed to get horizon account"), err)
+		return xdr.ScVal{}, err
+	}
+	op := BuildContractCallOp(acc, xdr.ScSymbol(fname), args, contract)
+	result, _, err := simulateTransaction(ctx, c.tr.syncHorizonClient(), c.tr.network, acc, op)
+	if err != nil {
+		return xdr.ScVal{}, err
+	}
+	if len(result.Results) != 1 {
+		return xdr.ScVal{}, errors.New("invalid number of results")
 	}
+	var val xdr.ScVal
+	if err := xdr.SafeUnmarshalBase64(result.Results[0].XDR, &val); err != nil {
+		return xdr.ScVal{}, err
+	}
+	return val, nil
+}
 
-	hzClient := c.tr.GetHorizonClient()
+// InvokeSignedTx invokes a signed transaction. The sequence number is
+// reserved only after the simulation succeeded, so concurrent invocations do
+// not leave gaps in the sequence. The transaction is resubmitted according to
+// the RetryPolicy of the transactor, if it could not be executed, a *TxError
+// carrying the final status is returned.
+func (c *ContractBackend) InvokeSignedTx(ctx context.Context, fname string, callTxArgs xdr.ScVec, contractAddr xdr.ScAddress) (xdr.TransactionMeta, error) {
+	if c.tr.relayer != nil {
+		return c.tr.relayer.Relay(ctx, fname, callTxArgs, contractAddr, c.AuthorizeEntries)
+	}
+	if c.tr.pool != nil {
+		return c.invokeFromPool(ctx, fname, callTxArgs, contractAddr)
+	}
+	fnameXdr := xdr.ScSymbol(fname)
+	acc, err := c.tr.seq.Account(ctx)
+	if err != nil {
+		return xdr.TransactionMeta{}, errors.Join(errors.New("failed to load source account"), err)
+	}
 
-	c.tr.sender.SetHzClient(hzClient)
-	invokeHostFunctionOp := BuildContractCallOp(hzAcc, fnameXdr, callTxArgs, contractAddr)
-	preFlightOp, minFee, err := PreflightHostFunctions(hzClient, &hzAcc, *invokeHostFunctionOp)
+	send, err := c.defaultSend()
 	if err != nil {
 		return xdr.TransactionMeta{}, err
 	}
-	minFeeCustom := int64(100) //nolint:gomnd
-	txParams := GetBaseTransactionParamsWithFee(&hzAcc, minFee+minFeeCustom, &preFlightOp)
-	txUnsigned, err := txnbuild.NewTransaction(txParams)
+
+	invokeHostFunctionOp := BuildContractCallOp(acc, fnameXdr, callTxArgs, contractAddr)
+	preFlightOp, minFee, err := c.preflightRestoring(ctx, c.tr.seq, send, acc, *invokeHostFunctionOp)
 	if err != nil {
-		return xdr.TransactionMeta{}, errors.Join(errors.New("error building Transaction"), err)
+		return xdr.TransactionMeta{}, err
 	}
-	txMeta, err := c.tr.sender.SignSendTx(*txUnsigned)
+	feeReq := FeeRequest{Function: fname, ResourceFee: minFee}
+	return c.submit(ctx, c.tr.seq, send, feeReq, &preFlightOp)
+}
+
+// applyFee chooses the fee of the operation and declares the resource fee
+// in a copy of its Soroban data. It returns the operation and the total fee
+// of the transaction.
+func (c *ContractBackend) applyFee(ctx context.Context, req FeeRequest, op txnbuild.Operation) (txnbuild.Operation, int64, error) {
+	req.FeeBump = c.tr.sponsor != nil
+	fee, err := c.tr.fees.Fee(ctx, req)
 	if err != nil {
-		return xdr.TransactionMeta{}, errors.Join(errors.New("sending tx"), err)
+		return nil, 0, err
 	}
+	return withResourceFee(op, fee.Resource), fee.Total(), nil
+}
 
-	return txMeta, nil
+// withResourceFee returns a copy of the Soroban operation with the given
+// resource fee declared in its Soroban data.
+func withResourceFee(op txnbuild.Operation, resourceFee int64) txnbuild.Operation {
+	setFee := func(ext xdr.TransactionExt) xdr.TransactionExt {
+		if ext.SorobanData == nil {
+			return ext
+		}
+		data := *ext.SorobanData
+		data.ResourceFee = xdr.Int64(resourceFee)
+		ext.SorobanData = &data
+		return ext
+	}
+	switch o := op.(type) {
+	case *txnbuild.InvokeHostFunction:
+		cp := *o
+		cp.Ext = setFee(cp.Ext)
+		return &cp
+	case *txnbuild.RestoreFootprint:
+		cp := *o
+		cp.Ext = setFee(cp.Ext)
+		return &cp
+	case *txnbuild.ExtendFootprintTtl:
+		cp := *o
+		cp.Ext = setFee(cp.Ext)
+		return &cp
+	default:
+		return op
+	}
 }
 
 // StringToScAddress converts a string to a xdr.ScAddress.
@@ -0,0 +1,182 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/stellar/go/xdr"
+)
+
+// ContractError is an error code returned by the Perun contract. The values
+// mirror the Error enum of the contract, so callers can branch with
+// errors.Is(err, client.ErrAlreadyFunded) etc.
+type ContractError uint32
+
+// Errors of the Perun contract.
+const (
+	ErrChannelIDMismatch             ContractError = 1
+	ErrInvalidVersionNumber          ContractError = 2
+	ErrOpenOnFinalState              ContractError = 3
+	ErrChannelAlreadyExists          ContractError = 4
+	ErrChannelNotFound               ContractError = 5
+	ErrEncoding                      ContractError = 6
+	ErrInvalidActor                  ContractError = 7
+	ErrAlreadyFunded                 ContractError = 8
+	ErrCloseOnNonFinalState          ContractError = 9
+	ErrInvalidSignature              ContractError = 10
+	ErrOperationOnUnfundedChannel    ContractError = 11
+	ErrWithdrawOnOpenChannel         ContractError = 12
+	ErrDisputeOnClosedChannel        ContractError = 13
+	ErrInvalidStateTransition        ContractError = 14
+	ErrForceCloseOnClosedChannel     ContractError = 15
+	ErrForceCloseOnUndisputedChannel ContractError = 16
+	ErrTimelockNotExpired            ContractError = 17
+	ErrAbortFundingOnFundedChannel   ContractError = 18
+	ErrAbortFundingOnClosedChannel   ContractError = 19
+	ErrAbortFundingOnDisputedChannel ContractError = 20
+	ErrAbortFundingWithoutFunds      ContractError = 21
+	ErrVerificationFailed            ContractError = 22
+	ErrInvalidPubKeyType             ContractError = 23
+	ErrInvalidKeyType                ContractError = 24
+	ErrConversion                    ContractError = 25
+	ErrWrongAssetType                ContractError = 26
+	ErrInvalidXdrSize                ContractError = 27
+	ErrInvalidChanIDSize             ContractError = 28
+	ErrWrongChannelType              ContractError = 29
+	ErrInvalidAddressType            ContractError = 30
+)
+
+var contractErrorNames = map[ContractError]string{
+	ErrChannelIDMismatch:             "channel ID mismatch",
+	ErrInvalidVersionNumber:          "invalid version number",
+	ErrOpenOnFinalState:              "open on final state",
+	ErrChannelAlreadyExists:          "channel already exists",
+	ErrChannelNotFound:               "channel not found",
+	ErrEncoding:                      "encoding error",
+	ErrInvalidActor:                  "invalid actor",
+	ErrAlreadyFunded:                 "already funded",
+	ErrCloseOnNonFinalState:          "close on non-final state",
+	ErrInvalidSignature:              "invalid signature",
+	ErrOperationOnUnfundedChannel:    "operation on unfunded channel",
+	ErrWithdrawOnOpenChannel:         "withdraw on open channel",
+	ErrDisputeOnClosedChannel:        "dispute on closed channel",
+	ErrInvalidStateTransition:        "invalid state transition",
+	ErrForceCloseOnClosedChannel:     "force close on closed channel",
+	ErrForceCloseOnUndisputedChannel: "force close on undisputed channel",
+	ErrTimelockNotExpired:            "timelock not expired",
+	ErrAbortFundingOnFundedChannel:   "abort funding on funded channel",
+	ErrAbortFundingOnClosedChannel:   "abort funding on closed channel",
+	ErrAbortFundingOnDisputedChannel: "abort funding on disputed channel",
+	ErrAbortFundingWithoutFunds:      "abort funding without funds",
+	ErrVerificationFailed:            "signature verification failed",
+	ErrInvalidPubKeyType:             "invalid public key type",
+	ErrInvalidKeyType:                "invalid key type",
+	ErrConversion:                    "conversion error",
+	ErrWrongAssetType:                "wrong asset type",
+	ErrInvalidXdrSize:                "invalid XDR size",
+	ErrInvalidChanIDSize:             "invalid channel ID size",
+	ErrWrongChannelType:              "wrong channel type",
+	ErrInvalidAddressType:            "invalid address type",
+}
+
+func (e ContractError) Error() string {
+	if name, ok := contractErrorNames[e]; ok {
+		return "perun contract: " + name
+	}
+	return fmt.Sprintf("perun contract: error #%d", uint32(e))
+}
+
+// ContractErrorFromScVal returns the contract error contained in an ScVal of
+// type error, if any.
+func ContractErrorFromScVal(val xdr.ScVal) (ContractError, bool) {
+	scErr, ok := val.GetError()
+	if !ok || scErr.Type != xdr.ScErrorTypeSceContract || scErr.ContractCode == nil {
+		return 0, false
+	}
+	return ContractError(*scErr.ContractCode), true
+}
+
+// ContractErrorFromEvents returns the contract error reported by the
+// diagnostic events of a failed invocation. The host emits an "error" event
+// whose second topic is the error.
+func ContractErrorFromEvents(events []xdr.DiagnosticEvent) (ContractError, bool) {
+	for _, ev := range events {
+		body, ok := ev.Event.Body.GetV0()
+		if !ok || len(body.Topics) < 2 { //nolint:gomnd
+			continue
+		}
+		if sym, ok := body.Topics[0].GetSym(); !ok || sym != "error" {
+			continue
+		}
+		if code, ok := ContractErrorFromScVal(body.Topics[1]); ok {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// hostErrorRegex matches the contract error in a host error message.
+var hostErrorRegex = regexp.MustCompile(`Error\(Contract, #(\d+)\)`)
+
+// ContractErrorFromMessage returns the contract error contained in a host
+// error message like "HostError: Error(Contract, #5)".
+func ContractErrorFromMessage(msg string) (ContractError, bool) {
+	match := hostErrorRegex.FindStringSubmatch(msg)
+	if match == nil {
+		return 0, false
+	}
+	code, err := strconv.ParseUint(match[1], 10, 32) //nolint:gomnd
+	if err != nil {
+		return 0, false
+	}
+	return ContractError(code), true
+}
+
+// simulationError converts a failed simulation into an error. If the
+// failure was caused by the Perun contract, the error wraps the ContractError.
+func simulationError(result RPCSimulateTxResponse) error {
+	events := make([]xdr.DiagnosticEvent, 0, len(result.Events))
+	for _, evBase64 := range result.Events {
+		var ev xdr.DiagnosticEvent
+		if err := xdr.SafeUnmarshalBase64(evBase64, &ev); err == nil {
+			events = append(events, ev)
+		}
+	}
+	if code, ok := ContractErrorFromEvents(events); ok {
+		return fmt.Errorf("simulation failed: %w", code)
+	}
+	if code, ok := ContractErrorFromMessage(result.Error); ok {
+		return fmt.Errorf("simulation failed: %w", code)
+	}
+	return fmt.Errorf("simulation failed: %s", result.Error)
+}
+
+// failedTxError converts the result of a failed transaction into an error
+// wrapping ErrTxFailed and, if the diagnostic events reveal it, the
+// ContractError.
+func failedTxError(result RPCGetTxResponse) error {
+	var meta xdr.TransactionMeta
+	if err := xdr.SafeUnmarshalBase64(result.ResultMetaXdr, &meta); err == nil {
+		if v3, ok := meta.GetV3(); ok && v3.SorobanMeta != nil {
+			if code, ok := ContractErrorFromEvents(v3.SorobanMeta.DiagnosticEvents); ok {
+				return fmt.Errorf("%w: %w", ErrTxFailed, code)
+			}
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrTxFailed, result.ResultXdr)
+}
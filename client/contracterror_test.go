@@ -0,0 +1,97 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/perun-stellar-backend/client"
+)
+
+func contractErrorEvent(code uint32) xdr.DiagnosticEvent {
+	contractCode := xdr.Uint32(code)
+	errVal := xdr.ScVal{
+		Type:  xdr.ScValTypeScvError,
+		Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &contractCode},
+	}
+	sym := xdr.ScSymbol("error")
+	return xdr.DiagnosticEvent{
+		Event: xdr.ContractEvent{
+			Type: xdr.ContractEventTypeDiagnostic,
+			Body: xdr.ContractEventBody{
+				V: 0,
+				V0: &xdr.ContractEventV0{
+					Topics: []xdr.ScVal{{Type: xdr.ScValTypeScvSymbol, Sym: &sym}, errVal},
+					Data:   xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+				},
+			},
+		},
+	}
+}
+
+func TestContractErrorDecoding(t *testing.T) {
+	code, ok := client.ContractErrorFromEvents([]xdr.DiagnosticEvent{contractErrorEvent(17)})
+	require.True(t, ok)
+	require.Equal(t, client.ErrTimelockNotExpired, code)
+
+	code, ok = client.ContractErrorFromMessage("HostError: Error(Contract, #5)\n\nEvent log (newest first): ...")
+	require.True(t, ok)
+	require.Equal(t, client.ErrChannelNotFound, code)
+
+	_, ok = client.ContractErrorFromMessage("HostError: Error(Budget, ExceededLimit)")
+	require.False(t, ok)
+
+	require.Equal(t, "perun contract: already funded", client.ErrAlreadyFunded.Error())
+	require.Equal(t, "perun contract: error #99", client.ContractError(99).Error())
+}
+
+func TestSimulationContractError(t *testing.T) {
+	cb, rpc, submitted := newSubmitBackend(t, nil)
+	ev, err := xdr.MarshalBase64(contractErrorEvent(uint32(client.ErrAlreadyFunded)))
+	require.NoError(t, err)
+	rpc.handle("simulateTransaction", func(json.RawMessage) interface{} {
+		return client.RPCSimulateTxResponse{
+			Error:  "HostError: Error(Contract, #8)",
+			Events: []string{ev},
+		}
+	})
+
+	_, err = cb.InvokeSignedTx(context.Background(), "fund", xdr.ScVec{}, testContract)
+	require.ErrorIs(t, err, client.ErrAlreadyFunded)
+	require.Empty(t, *submitted)
+}
+
+func TestFailedTxContractError(t *testing.T) {
+	cb, rpc, _ := newSubmitBackend(t, nil)
+	meta, err := xdr.MarshalBase64(xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{
+		SorobanMeta: &xdr.SorobanTransactionMeta{
+			ReturnValue:      xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+			DiagnosticEvents: []xdr.DiagnosticEvent{contractErrorEvent(uint32(client.ErrForceCloseOnUndisputedChannel))},
+		},
+	}})
+	require.NoError(t, err)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusFailed, ResultMetaXdr: meta}
+	})
+
+	_, err = cb.InvokeSignedTx(context.Background(), "force_close", xdr.ScVec{}, testContract)
+	require.ErrorIs(t, err, client.ErrTxFailed)
+	require.ErrorIs(t, err, client.ErrForceCloseOnUndisputedChannel)
+}
@@ -0,0 +1,256 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// Names under which the deployment operations are passed to the FeeStrategy.
+const (
+	uploadWasmFunction     = "upload_contract_wasm"
+	createContractFunction = "create_contract"
+)
+
+// Deployment describes a contract instantiated by a Deployer.
+type Deployment struct {
+	// Address is the address of the contract instance.
+	Address xdr.ScAddress
+	// WasmHash is the hash of the contract code.
+	WasmHash xdr.Hash
+	// Uploaded reports whether the code was uploaded. It is false if the
+	// code was already installed on the ledger.
+	Uploaded bool
+}
+
+// Deployer uploads contract code and instantiates contracts from the account
+// of a ContractBackend. The deployer's account and the salt determine the
+// address of a contract, so a deployment can be scripted idempotently by
+// checking ContractAddress before deploying.
+type Deployer struct {
+	cb *ContractBackend
+}
+
+// NewDeployer creates a new Deployer sending its transactions via the given
+// ContractBackend.
+func NewDeployer(cb *ContractBackend) *Deployer {
+	return &Deployer{cb: cb}
+}
+
+// WasmHash returns the hash under which the contract code is installed.
+func WasmHash(wasm []byte) xdr.Hash {
+	return sha256.Sum256(wasm)
+}
+
+// SaltFromString derives a contract salt from a string.
+func SaltFromString(s string) xdr.Uint256 {
+	return sha256.Sum256([]byte(s))
+}
+
+// ContractAddress returns the address of the contract instantiated by the
+// deployer with the given salt on the network with the given passphrase.
+func ContractAddress(passphrase string, deployer xdr.ScAddress, salt xdr.Uint256) (xdr.ScAddress, error) {
+	preimage := xdr.HashIdPreimage{
+		Type: xdr.EnvelopeTypeEnvelopeTypeContractId,
+		ContractId: &xdr.HashIdPreimageContractId{
+			NetworkId:          network.ID(passphrase),
+			ContractIdPreimage: contractIDPreimage(deployer, salt),
+		},
+	}
+	preimageXdr, err := preimage.MarshalBinary()
+	if err != nil {
+		return xdr.ScAddress{}, err
+	}
+	id := xdr.Hash(sha256.Sum256(preimageXdr))
+	return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id}, nil
+}
+
+// BuildUploadWasmOp builds the operation uploading the contract code.
+func BuildUploadWasmOp(sourceAccount string, wasm []byte) *txnbuild.InvokeHostFunction {
+	// CAP-0047 - https://github.com/stellar/stellar-protocol/blob/master/core/cap-0047.md#creating-a-contract-using-invokehostfunctionop
+	return &txnbuild.InvokeHostFunction{
+		HostFunction: xdr.HostFunction{
+			Type: xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm,
+			Wasm: &wasm,
+		},
+		SourceAccount: sourceAccount,
+	}
+}
+
+// BuildCreateContractOp builds the operation instantiating the installed
+// contract code with the given hash. The source account is the deployer.
+func BuildCreateContractOp(sourceAccount string, wasmHash xdr.Hash, salt xdr.Uint256) (*txnbuild.InvokeHostFunction, error) {
+	deployer, err := accountScAddress(sourceAccount)
+	if err != nil {
+		return nil, err
+	}
+	return &txnbuild.InvokeHostFunction{
+		HostFunction: xdr.HostFunction{
+			Type: xdr.HostFunctionTypeHostFunctionTypeCreateContract,
+			CreateContract: &xdr.CreateContractArgs{
+				ContractIdPreimage: contractIDPreimage(deployer, salt),
+				Executable: xdr.ContractExecutable{
+					Type:     xdr.ContractExecutableTypeContractExecutableWasm,
+					WasmHash: &wasmHash,
+				},
+			},
+		},
+		SourceAccount: sourceAccount,
+	}, nil
+}
+
+func contractIDPreimage(deployer xdr.ScAddress, salt xdr.Uint256) xdr.ContractIdPreimage {
+	return xdr.ContractIdPreimage{
+		Type: xdr.ContractIdPreimageTypeContractIdPreimageFromAddress,
+		FromAddress: &xdr.ContractIdPreimageFromAddress{
+			Address: deployer,
+			Salt:    salt,
+		},
+	}
+}
+
+// ContractAddress returns the address of the contract the deployer
+// instantiates with the given salt.
+func (d *Deployer) ContractAddress(salt xdr.Uint256) (xdr.ScAddress, error) {
+	deployer, err := d.cb.accountAddress()
+	if err != nil {
+		return xdr.ScAddress{}, err
+	}
+	return ContractAddress(d.cb.tr.network.Passphrase, deployer, salt)
+}
+
+// ContractCodeLedgerKey returns the ledger key of the contract code with the
+// given hash.
+func ContractCodeLedgerKey(wasmHash xdr.Hash) xdr.LedgerKey {
+	return xdr.LedgerKey{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.LedgerKeyContractCode{Hash: wasmHash},
+	}
+}
+
+// IsInstalled reports whether contract code with the given hash is installed
+// on the ledger.
+func (d *Deployer) IsInstalled(ctx context.Context, wasmHash xdr.Hash) (bool, error) {
+	rpc := d.cb.tr.network.NewRPCClient()
+	defer rpc.Close()
+	result, err := getLedgerEntries(ctx, rpc, ContractCodeLedgerKey(wasmHash))
+	if err != nil {
+		return false, err
+	}
+	return len(result.Entries) > 0, nil
+}
+
+// Upload installs the contract code and returns its hash. If the code is
+// already installed, nothing is submitted and uploaded is false.
+func (d *Deployer) Upload(ctx context.Context, wasm []byte) (hash xdr.Hash, uploaded bool, err error) {
+	hash = WasmHash(wasm)
+	installed, err := d.IsInstalled(ctx, hash)
+	if err != nil {
+		return hash, false, errors.Join(errors.New("failed to look up contract code"), err)
+	}
+	if installed {
+		return hash, false, nil
+	}
+
+	source, err := d.cb.tr.GetAddress()
+	if err != nil {
+		return hash, false, err
+	}
+	if err := d.invoke(ctx, uploadWasmFunction, BuildUploadWasmOp(source, wasm)); err != nil {
+		return hash, false, fmt.Errorf("uploading contract code %x: %w", hash, err)
+	}
+	return hash, true, nil
+}
+
+// Instantiate creates a contract instance of the installed contract code
+// with the given hash and returns its address.
+func (d *Deployer) Instantiate(ctx context.Context, wasmHash xdr.Hash, salt xdr.Uint256) (xdr.ScAddress, error) {
+	source, err := d.cb.tr.GetAddress()
+	if err != nil {
+		return xdr.ScAddress{}, err
+	}
+	op, err := BuildCreateContractOp(source, wasmHash, salt)
+	if err != nil {
+		return xdr.ScAddress{}, err
+	}
+	addr, err := d.ContractAddress(salt)
+	if err != nil {
+		return xdr.ScAddress{}, err
+	}
+	if err := d.invoke(ctx, createContractFunction, op); err != nil {
+		return xdr.ScAddress{}, fmt.Errorf("instantiating contract code %x: %w", wasmHash, err)
+	}
+	return addr, nil
+}
+
+// Deploy uploads the contract code, unless it is already installed, and
+// instantiates it with the given salt.
+func (d *Deployer) Deploy(ctx context.Context, wasm []byte, salt xdr.Uint256) (Deployment, error) {
+	hash, uploaded, err := d.Upload(ctx, wasm)
+	if err != nil {
+		return Deployment{}, err
+	}
+	addr, err := d.Instantiate(ctx, hash, salt)
+	if err != nil {
+		return Deployment{}, err
+	}
+	return Deployment{Address: addr, WasmHash: hash, Uploaded: uploaded}, nil
+}
+
+// DeployFile deploys the contract code read from the given wasm file.
+func (d *Deployer) DeployFile(ctx context.Context, path string, salt xdr.Uint256) (Deployment, error) {
+	wasm, err := os.ReadFile(path)
+	if err != nil {
+		return Deployment{}, err
+	}
+	return d.Deploy(ctx, wasm, salt)
+}
+
+// invoke simulates and submits the host function from the deployer's account.
+func (d *Deployer) invoke(ctx context.Context, fname string, op *txnbuild.InvokeHostFunction) error {
+	c := d.cb
+	send, err := c.defaultSend()
+	if err != nil {
+		return err
+	}
+	acc, err := c.tr.seq.Account(ctx)
+	if err != nil {
+		return errors.Join(errors.New("failed to load source account"), err)
+	}
+	preFlightOp, minFee, err := c.preflightRestoring(ctx, c.tr.seq, send, acc, *op)
+	if err != nil {
+		return err
+	}
+	feeReq := FeeRequest{Function: fname, ResourceFee: minFee}
+	_, err = c.submit(ctx, c.tr.seq, send, feeReq, &preFlightOp)
+	return err
+}
+
+// accountScAddress converts a Stellar account address to an ScAddress.
+func accountScAddress(address string) (xdr.ScAddress, error) {
+	accountID, err := xdr.AddressToAccountId(address)
+	if err != nil {
+		return xdr.ScAddress{}, errors.Join(errors.New("invalid account address"), err)
+	}
+	return xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeAccount, accountID)
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/perun-stellar-backend/client"
+)
+
+var testWasm = []byte("\x00asm\x01\x00\x00\x00")
+
+// newDeployBackend sets up a ContractBackend against a fake soroban-rpc on
+// which the test wasm is installed if installed is set. It returns the host
+// functions of the submitted transactions.
+func newDeployBackend(t *testing.T, installed bool) (*client.ContractBackend, *[]xdr.HostFunction) {
+	cb, rpc, _ := newSubmitBackend(t, nil)
+	account := accountEntryXdr(t, keypair.MustRandom(), 41)
+	codeKey := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: client.WasmHash(testWasm)}}
+	rpc.handle("getLedgerEntries", func(params json.RawMessage) interface{} {
+		var req struct {
+			Keys []string `json:"keys"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		var res client.RPCGetLedgerEntriesResponse
+		for _, encoded := range req.Keys {
+			var key xdr.LedgerKey
+			require.NoError(t, xdr.SafeUnmarshalBase64(encoded, &key))
+			switch {
+			case key.Type == xdr.LedgerEntryTypeAccount:
+				res.Entries = append(res.Entries, client.RPCLedgerEntry{Key: encoded, XDR: account})
+			case installed && key.Equals(codeKey):
+				res.Entries = append(res.Entries, client.RPCLedgerEntry{Key: encoded})
+			}
+		}
+		return res
+	})
+	var submitted []xdr.HostFunction
+	rpc.handle("sendTransaction", func(params json.RawMessage) interface{} {
+		var req struct {
+			Transaction string `json:"transaction"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		generic, err := txnbuild.TransactionFromXDR(req.Transaction)
+		require.NoError(t, err)
+		tx, ok := generic.Transaction()
+		require.True(t, ok)
+		submitted = append(submitted, tx.Operations()[0].(*txnbuild.InvokeHostFunction).HostFunction)
+		return client.RPCSendTxResponse{Status: client.TxStatusPending}
+	})
+	meta := successMeta(t)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+	return cb, &submitted
+}
+
+func TestDeployerDeploy(t *testing.T) {
+	cb, submitted := newDeployBackend(t, false)
+	deployer := client.NewDeployer(cb)
+	salt := client.SaltFromString("perun")
+
+	deployment, err := deployer.Deploy(context.Background(), testWasm, salt)
+	require.NoError(t, err)
+	require.True(t, deployment.Uploaded)
+	require.Equal(t, client.WasmHash(testWasm), deployment.WasmHash)
+
+	require.Len(t, *submitted, 2)
+	upload, create := (*submitted)[0], (*submitted)[1]
+	require.Equal(t, xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm, upload.Type)
+	require.Equal(t, testWasm, *upload.Wasm)
+	require.Equal(t, xdr.HostFunctionTypeHostFunctionTypeCreateContract, create.Type)
+	require.Equal(t, deployment.WasmHash, *create.CreateContract.Executable.WasmHash)
+	require.Equal(t, salt, create.CreateContract.ContractIdPreimage.FromAddress.Salt)
+
+	source, err := cb.GetTransactor().GetAddress()
+	require.NoError(t, err)
+	deployerAddr, err := xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeAccount, xdr.MustAddress(source))
+	require.NoError(t, err)
+	require.Equal(t, deployerAddr, create.CreateContract.ContractIdPreimage.FromAddress.Address)
+	want, err := client.ContractAddress(client.StandaloneNetwork().Passphrase, deployerAddr, salt)
+	require.NoError(t, err)
+	require.Equal(t, want, deployment.Address)
+}
+
+func TestDeployerSkipsInstalledCode(t *testing.T) {
+	cb, submitted := newDeployBackend(t, true)
+	deployer := client.NewDeployer(cb)
+
+	deployment, err := deployer.Deploy(context.Background(), testWasm, client.SaltFromString("perun"))
+	require.NoError(t, err)
+	require.False(t, deployment.Uploaded)
+	require.Len(t, *submitted, 1)
+	require.Equal(t, xdr.HostFunctionTypeHostFunctionTypeCreateContract, (*submitted)[0].Type)
+}
+
+func TestContractAddress(t *testing.T) {
+	deployer, err := xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeAccount, xdr.MustAddress(keypair.MustRandom().Address()))
+	require.NoError(t, err)
+	passphrase := client.StandaloneNetwork().Passphrase
+
+	a, err := client.ContractAddress(passphrase, deployer, client.SaltFromString("a"))
+	require.NoError(t, err)
+	again, err := client.ContractAddress(passphrase, deployer, client.SaltFromString("a"))
+	require.NoError(t, err)
+	require.Equal(t, a, again)
+	require.Equal(t, xdr.ScAddressTypeScAddressTypeContract, a.Type)
+
+	b, err := client.ContractAddress(passphrase, deployer, client.SaltFromString("b"))
+	require.NoError(t, err)
+	require.NotEqual(t, a, b)
+	other, err := client.ContractAddress("other network", deployer, client.SaltFromString("a"))
+	require.NoError(t, err)
+	require.NotEqual(t, a, other)
+}
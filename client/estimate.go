@@ -0,0 +1,205 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/xdr"
+	pchannel "perun.network/go-perun/channel"
+	pwallet "perun.network/go-perun/wallet"
+)
+
+// Estimate is the estimated cost of a contract call, as returned by a
+// simulation. The fees are chosen by the FeeStrategy of the ContractBackend.
+type Estimate struct {
+	Function     string
+	InclusionFee int64
+	ResourceFee  int64
+	Instructions uint32
+	ReadBytes    uint32
+	WriteBytes   uint32
+	Footprint    xdr.LedgerFootprint
+}
+
+// TotalFee returns the total fee of the contract call.
+func (e Estimate) TotalFee() int64 {
+	return e.InclusionFee + e.ResourceFee
+}
+
+// Estimate simulates a call of the contract function without submitting it.
+func (c *ContractBackend) Estimate(ctx context.Context, fname string, args xdr.ScVec, contract xdr.ScAddress) (Estimate, error) {
+	acc, err := c.tr.seq.Account(ctx)
+	if err != nil {
+		return Estimate{}, errors.Join(errors.New("failed to load source account"), err)
+	}
+	op := BuildContractCallOp(acc, xdr.ScSymbol(fname), args, contract)
+	result, transactionData, err := simulateTransaction(ctx, c.tr.syncHorizonClient(), c.tr.network, acc, op)
+	if err != nil {
+		return Estimate{}, fmt.Errorf("estimating %s: %w", fname, err)
+	}
+	fee, err := c.tr.fees.Fee(ctx, FeeRequest{Function: fname, ResourceFee: result.MinResourceFee, FeeBump: c.tr.sponsor != nil})
+	if err != nil {
+		return Estimate{}, err
+	}
+	resources := transactionData.Resources
+	return Estimate{
+		Function:     fname,
+		InclusionFee: fee.Inclusion,
+		ResourceFee:  fee.Resource,
+		Instructions: uint32(resources.Instructions),
+		ReadBytes:    uint32(resources.ReadBytes),
+		WriteBytes:   uint32(resources.WriteBytes),
+		Footprint:    resources.Footprint,
+	}, nil
+}
+
+// EstimateOpen estimates the cost of opening the channel.
+func (c *ContractBackend) EstimateOpen(ctx context.Context, perunAddr xdr.ScAddress, params *pchannel.Params, state *pchannel.State) (Estimate, error) {
+	args, err := buildOpenTxArgs(*params, *state)
+	if err != nil {
+		return Estimate{}, err
+	}
+	return c.Estimate(ctx, "open", args, perunAddr)
+}
+
+// EstimateFund estimates the cost of funding the channel by the given party.
+func (c *ContractBackend) EstimateFund(ctx context.Context, perunAddr xdr.ScAddress, chanID pchannel.ID, funderIdx bool) (Estimate, error) {
+	args, err := buildChanIdxTxArgs(chanID, funderIdx)
+	if err != nil {
+		return Estimate{}, err
+	}
+	return c.Estimate(ctx, "fund", args, perunAddr)
+}
+
+// EstimateClose estimates the cost of closing the channel with a final state.
+func (c *ContractBackend) EstimateClose(ctx context.Context, perunAddr xdr.ScAddress, state *pchannel.State, sigs []pwallet.Sig) (Estimate, error) {
+	args, err := buildSignedStateTxArgs(*state, sigs)
+	if err != nil {
+		return Estimate{}, err
+	}
+	return c.Estimate(ctx, "close", args, perunAddr)
+}
+
+// EstimateDispute estimates the cost of disputing the channel with the given state.
+func (c *ContractBackend) EstimateDispute(ctx context.Context, perunAddr xdr.ScAddress, state *pchannel.State, sigs []pwallet.Sig) (Estimate, error) {
+	args, err := buildSignedStateTxArgs(*state, sigs)
+	if err != nil {
+		return Estimate{}, err
+	}
+	return c.Estimate(ctx, "dispute", args, perunAddr)
+}
+
+// EstimateForceClose estimates the cost of force-closing the channel.
+func (c *ContractBackend) EstimateForceClose(ctx context.Context, perunAddr xdr.ScAddress, chanID pchannel.ID) (Estimate, error) {
+	args, err := buildChanIDTxArgs(chanID)
+	if err != nil {
+		return Estimate{}, err
+	}
+	return c.Estimate(ctx, "force_close", args, perunAddr)
+}
+
+// EstimateWithdraw estimates the cost of withdrawing from the channel.
+func (c *ContractBackend) EstimateWithdraw(ctx context.Context, perunAddr xdr.ScAddress, chanID pchannel.ID, withdrawerIdx, oneWithdrawer bool,
+) (Estimate, error) {
+	args, err := buildWithdrawTxArgs(chanID, withdrawerIdx, oneWithdrawer)
+	if err != nil {
+		return Estimate{}, err
+	}
+	return c.Estimate(ctx, "withdraw", args, perunAddr)
+}
+
+// OperationsEstimate aggregates the estimates of the operations on an open
+// channel.
+type OperationsEstimate struct {
+	// Estimates holds the estimates of the operations that could be
+	// simulated, keyed by the operation.
+	Estimates map[string]Estimate
+	// Failed holds the errors of the operations that could not be simulated,
+	// keyed by the operation.
+	Failed map[string]error
+}
+
+// Complete reports whether all operations could be simulated.
+func (o OperationsEstimate) Complete() bool {
+	return len(o.Failed) == 0
+}
+
+// TotalFee returns the sum of the fees of all simulated operations. As it
+// contains the cooperative close as well as the dispute, it is an upper bound
+// of the fees of a single channel after its opening.
+func (o OperationsEstimate) TotalFee() int64 {
+	var total int64
+	for _, e := range o.Estimates {
+		total += e.TotalFee()
+	}
+	return total
+}
+
+// EstimateOperations estimates the operations on an open channel: fund by
+// both parties, close, dispute, force_close and withdraw by both parties.
+// Each operation is simulated against the current ledger, which is why the
+// channel must already be open; otherwise ErrChannelNotFound is returned. The
+// opening itself is estimated with EstimateOpen before the channel is opened.
+// Close and dispute are simulated with the given signatures of the state and
+// are reported as failed if none are given.
+func (c *ContractBackend) EstimateOperations(ctx context.Context, perunAddr xdr.ScAddress,
+	state *pchannel.State, sigs []pwallet.Sig,
+) (OperationsEstimate, error) {
+	id := state.ID
+	if _, err := c.GetChannelFromLedger(ctx, perunAddr, id); err != nil {
+		return OperationsEstimate{}, fmt.Errorf("reading channel %x: %w", id, err)
+	}
+	steps := []struct {
+		name     string
+		estimate func() (Estimate, error)
+	}{
+		{"fund_a", func() (Estimate, error) { return c.EstimateFund(ctx, perunAddr, id, false) }},
+		{"fund_b", func() (Estimate, error) { return c.EstimateFund(ctx, perunAddr, id, true) }},
+		{"close", func() (Estimate, error) { return c.estimateSigned(ctx, perunAddr, state, sigs, c.EstimateClose) }},
+		{"dispute", func() (Estimate, error) { return c.estimateSigned(ctx, perunAddr, state, sigs, c.EstimateDispute) }},
+		{"force_close", func() (Estimate, error) { return c.EstimateForceClose(ctx, perunAddr, id) }},
+		{"withdraw_a", func() (Estimate, error) { return c.EstimateWithdraw(ctx, perunAddr, id, false, false) }},
+		{"withdraw_b", func() (Estimate, error) { return c.EstimateWithdraw(ctx, perunAddr, id, true, false) }},
+	}
+
+	ops := OperationsEstimate{
+		Estimates: make(map[string]Estimate, len(steps)),
+		Failed:    make(map[string]error),
+	}
+	for _, step := range steps {
+		estimate, err := step.estimate()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return OperationsEstimate{}, ctxErr
+		}
+		if err != nil {
+			ops.Failed[step.name] = err
+			continue
+		}
+		ops.Estimates[step.name] = estimate
+	}
+	return ops, nil
+}
+
+func (c *ContractBackend) estimateSigned(ctx context.Context, perunAddr xdr.ScAddress, state *pchannel.State, sigs []pwallet.Sig,
+	estimate func(context.Context, xdr.ScAddress, *pchannel.State, []pwallet.Sig) (Estimate, error),
+) (Estimate, error) {
+	if len(sigs) != state.NumParts() {
+		return Estimate{}, errors.New("signatures of all participants are required")
+	}
+	return estimate(ctx, perunAddr, state, sigs)
+}
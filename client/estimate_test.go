@@ -0,0 +1,166 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+	pchannel "perun.network/go-perun/channel"
+	ptest "perun.network/go-perun/channel/test"
+	pwallet "perun.network/go-perun/wallet"
+	polytest "polycry.pt/poly-go/test"
+
+	chtest "perun.network/perun-stellar-backend/channel/test"
+	"perun.network/perun-stellar-backend/client"
+	"perun.network/perun-stellar-backend/wire"
+)
+
+// estimateBackend returns a backend whose simulations return the same
+// resources for every function. The channel is only on the ledger if opened.
+func estimateBackend(t *testing.T, channel *wire.Channel) (*client.ContractBackend, *[]string, xdr.LedgerFootprint) {
+	t.Helper()
+	cb, rpc, submitted := newSubmitBackend(t, client.NewFixedFeeStrategy(200, 10))
+	t.Cleanup(func() { require.Empty(t, *submitted, "estimation must not submit transactions") })
+	rpc.mu.Lock()
+	loadAccount := rpc.handlers["getLedgerEntries"]
+	rpc.mu.Unlock()
+	rpc.handle("getLedgerEntries", func(params json.RawMessage) interface{} {
+		var req struct {
+			Keys []string `json:"keys"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		var key xdr.LedgerKey
+		require.NoError(t, xdr.SafeUnmarshalBase64(req.Keys[0], &key))
+		if key.Type != xdr.LedgerEntryTypeContractData {
+			return loadAccount(params)
+		}
+		if channel == nil {
+			return client.RPCGetLedgerEntriesResponse{}
+		}
+		return client.RPCGetLedgerEntriesResponse{
+			Entries: []client.RPCLedgerEntry{{XDR: channelEntryXdr(t, testContract, *channel)}},
+		}
+	})
+
+	footprint := xdr.LedgerFootprint{
+		ReadWrite: []xdr.LedgerKey{{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.LedgerKeyContractData{Contract: testContract, Key: xdr.ScVal{Type: xdr.ScValTypeScvVoid}, Durability: xdr.ContractDataDurabilityPersistent}}},
+	}
+	txData, err := xdr.MarshalBase64(xdr.SorobanTransactionData{Resources: xdr.SorobanResources{
+		Footprint:    footprint,
+		Instructions: 4_000_000,
+		ReadBytes:    1_200,
+		WriteBytes:   800,
+	}})
+	require.NoError(t, err)
+	retXdr, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	require.NoError(t, err)
+	var simulated []string
+	rpc.handle("simulateTransaction", func(params json.RawMessage) interface{} {
+		var req struct {
+			Transaction string `json:"transaction"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		generic, err := txnbuild.TransactionFromXDR(req.Transaction)
+		require.NoError(t, err)
+		tx, ok := generic.Transaction()
+		require.True(t, ok)
+		fname := tx.Operations()[0].(*txnbuild.InvokeHostFunction).HostFunction.InvokeContract.FunctionName
+		simulated = append(simulated, string(fname))
+		return client.RPCSimulateTxResponse{
+			TransactionData: txData,
+			Results:         []client.RPCSimulateHostFunctionResult{{XDR: retXdr}},
+			MinResourceFee:  50_000,
+		}
+	})
+	return cb, &simulated, footprint
+}
+
+func estimateParamsAndState(t *testing.T) (*pchannel.Params, *pchannel.State) {
+	t.Helper()
+	rng := polytest.Prng(t)
+	return ptest.NewRandomParamsAndState(rng,
+		ptest.WithNumParts(2),
+		ptest.WithBackend(stellarBackendID),
+		ptest.WithAssets(chtest.NewRandomStellarAsset(), chtest.NewRandomStellarAsset()),
+		ptest.WithNumLocked(0),
+		ptest.WithoutApp(),
+		ptest.WithLedgerChannel(true),
+		ptest.WithVirtualChannel(false),
+	)
+}
+
+func TestEstimateOpen(t *testing.T) {
+	cb, simulated, footprint := estimateBackend(t, nil)
+	params, state := estimateParamsAndState(t)
+
+	estimate, err := cb.EstimateOpen(context.Background(), testContract, params, state)
+	require.NoError(t, err)
+	require.Equal(t, []string{"open"}, *simulated)
+	require.Equal(t, client.Estimate{
+		Function:     "open",
+		InclusionFee: 200,
+		ResourceFee:  55_000,
+		Instructions: 4_000_000,
+		ReadBytes:    1_200,
+		WriteBytes:   800,
+		Footprint:    footprint,
+	}, estimate)
+	require.Equal(t, int64(55_200), estimate.TotalFee())
+}
+
+func TestEstimateOperationsBeforeOpen(t *testing.T) {
+	cb, simulated, _ := estimateBackend(t, nil)
+	_, state := estimateParamsAndState(t)
+
+	// The operations would only fail with ErrChannelNotFound in simulation.
+	_, err := cb.EstimateOperations(context.Background(), testContract, state, nil)
+	require.ErrorIs(t, err, client.ErrChannelNotFound)
+	require.Empty(t, *simulated)
+}
+
+func TestEstimateOperations(t *testing.T) {
+	params, state := estimateParamsAndState(t)
+	wireParams, err := wire.MakeParams(*params)
+	require.NoError(t, err)
+	wireState, err := wire.MakeState(*state)
+	require.NoError(t, err)
+	channel := wire.MakeChannel(wireParams, wireState, wire.Control{})
+	cb, simulated, _ := estimateBackend(t, &channel)
+	ctx := context.Background()
+
+	ops, err := cb.EstimateOperations(ctx, testContract, state, nil)
+	require.NoError(t, err)
+	require.False(t, ops.Complete())
+	require.Len(t, ops.Estimates, 5)
+	require.Len(t, ops.Failed, 2)
+	require.Error(t, ops.Failed["close"])
+	require.Error(t, ops.Failed["dispute"])
+
+	*simulated = nil
+	sigs := []pwallet.Sig{make([]byte, 64), make([]byte, 64)}
+	ops, err = cb.EstimateOperations(ctx, testContract, state, sigs)
+	require.NoError(t, err)
+	require.True(t, ops.Complete())
+	require.Equal(t, []string{"fund", "fund", "close", "dispute", "force_close", "withdraw", "withdraw"}, *simulated)
+	for _, op := range []string{"fund_a", "fund_b", "close", "dispute", "force_close", "withdraw_a", "withdraw_b"} {
+		require.Equal(t, int64(55_200), ops.Estimates[op].TotalFee(), op)
+	}
+	require.Equal(t, int64(7*55_200), ops.TotalFee())
+}
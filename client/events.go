@@ -0,0 +1,248 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"strconv"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// MaxEventsPerRequest is the maximum number of events requested by a single
+// getEvents call.
+const MaxEventsPerRequest = 100
+
+// ErrEventsPruned is returned if the events after a cursor are no longer
+// retained by soroban-rpc.
+var ErrEventsPruned = errors.New("events are no longer retained")
+
+// EventSource streams the events emitted by the Perun contract. It is
+// implemented by Invokers that can subscribe to contract events instead of
+// polling the channel.
+type EventSource interface {
+	// LatestEventCursor returns a cursor pointing behind the events of the
+	// latest ledger.
+	LatestEventCursor(ctx context.Context) (EventCursor, error)
+	// PerunEvents returns the events emitted by the Perun contract after the
+	// cursor, in order, and the cursor to continue from. If the events after
+	// the cursor are no longer retained, ErrEventsPruned is returned.
+	PerunEvents(ctx context.Context, perunAddr xdr.ScAddress, cursor EventCursor) ([]xdr.ContractEvent, EventCursor, error)
+}
+
+var _ EventSource = (*ContractBackend)(nil)
+
+// EventCursor points into the event stream of the network. If Token is set,
+// events after the paging token are read, otherwise events starting at
+// StartLedger.
+type EventCursor struct {
+	StartLedger uint32
+	Token       string
+}
+
+// RPCEvent represents a single event of the getEvents response.
+type RPCEvent struct {
+	Type                     string   `json:"type"`
+	Ledger                   uint32   `json:"ledger"`
+	ContractID               string   `json:"contractId"`
+	ID                       string   `json:"id"`
+	PagingToken              string   `json:"pagingToken"`
+	Topic                    []string `json:"topic"`
+	Value                    string   `json:"value"`
+	InSuccessfulContractCall bool     `json:"inSuccessfulContractCall"`
+	TxHash                   string   `json:"txHash"`
+}
+
+// RPCGetEventsResponse represents the type of the RPCGetEventsResponse.
+type RPCGetEventsResponse struct {
+	Events       []RPCEvent `json:"events"`
+	LatestLedger uint32     `json:"latestLedger"`
+	Cursor       string     `json:"cursor"`
+}
+
+type rpcEventFilter struct {
+	Type        string   `json:"type"`
+	ContractIDs []string `json:"contractIds"`
+}
+
+type rpcPagination struct {
+	Cursor string `json:"cursor,omitempty"`
+	Limit  uint   `json:"limit"`
+}
+
+type rpcGetEventsRequest struct {
+	StartLedger uint32           `json:"startLedger,omitempty"`
+	Filters     []rpcEventFilter `json:"filters"`
+	Pagination  rpcPagination    `json:"pagination"`
+}
+
+// LatestEventCursor returns a cursor pointing behind the events of the
+// latest ledger.
+func (c *ContractBackend) LatestEventCursor(ctx context.Context) (EventCursor, error) {
+	rpc := c.tr.network.NewRPCClient()
+	defer rpc.Close()
+	latest, err := getLatestLedger(ctx, rpc)
+	if err != nil {
+		return EventCursor{}, err
+	}
+	return EventCursor{StartLedger: latest + 1}, nil
+}
+
+// PerunEvents reads the events emitted by the Perun contract after the
+// cursor using getEvents. It reads further pages while they are full, so that
+// all events up to the latest ledger are returned. If a further page cannot be
+// read, the events read so far are returned with the cursor behind them.
+// Events of failed contract calls are omitted.
+func (c *ContractBackend) PerunEvents(ctx context.Context, perunAddr xdr.ScAddress, cursor EventCursor) ([]xdr.ContractEvent, EventCursor, error) {
+	contractID, err := perunAddr.String()
+	if err != nil {
+		return nil, cursor, err
+	}
+	rpc := c.tr.network.NewRPCClient()
+	defer rpc.Close()
+
+	events, next, full, err := getEventsPage(ctx, rpc, contractID, cursor)
+	if err != nil {
+		return nil, cursor, err
+	}
+	for full {
+		var page []xdr.ContractEvent
+		var pageNext EventCursor
+		page, pageNext, full, err = getEventsPage(ctx, rpc, contractID, next)
+		if err != nil {
+			log.Printf("Reading further events failed, continuing later: %v", err)
+			break
+		}
+		if pageNext == next {
+			break
+		}
+		events, next = append(events, page...), pageNext
+	}
+	return events, next, nil
+}
+
+// getEventsPage reads a page of the events of the contract after the cursor.
+// It reports whether the page is full, i.e., further events may follow.
+func getEventsPage(ctx context.Context, rpc *jrpc2.Client, contractID string, cursor EventCursor,
+) (events []xdr.ContractEvent, next EventCursor, full bool, err error) {
+	req := rpcGetEventsRequest{
+		Filters:    []rpcEventFilter{{Type: "contract", ContractIDs: []string{contractID}}},
+		Pagination: rpcPagination{Cursor: cursor.Token, Limit: MaxEventsPerRequest},
+	}
+	if cursor.Token == "" {
+		req.StartLedger = cursor.StartLedger
+	}
+
+	var result RPCGetEventsResponse
+	if err := rpc.CallResult(ctx, "getEvents", req, &result); err != nil {
+		pruned, future := ledgerRangeError(err, cursor)
+		switch {
+		case pruned:
+			return nil, cursor, false, errors.Join(ErrEventsPruned, err)
+		case future:
+			// No ledger was closed since the cursor was created.
+			return nil, cursor, false, nil
+		default:
+			return nil, cursor, false, err
+		}
+	}
+
+	events = make([]xdr.ContractEvent, 0, len(result.Events))
+	for _, ev := range result.Events {
+		if !ev.InSuccessfulContractCall {
+			continue
+		}
+		decoded, err := decodeRPCEvent(ev)
+		if err != nil {
+			return nil, cursor, false, err
+		}
+		events = append(events, decoded)
+	}
+
+	next = cursor
+	switch {
+	case result.Cursor != "":
+		next = EventCursor{Token: result.Cursor}
+	case len(result.Events) > 0:
+		last := result.Events[len(result.Events)-1]
+		next = EventCursor{Token: last.PagingToken}
+		if next.Token == "" {
+			next.Token = last.ID
+		}
+	}
+	return events, next, len(result.Events) >= MaxEventsPerRequest, nil
+}
+
+// decodeRPCEvent decodes an event of the getEvents response.
+func decodeRPCEvent(ev RPCEvent) (xdr.ContractEvent, error) {
+	rawID, err := strkey.Decode(strkey.VersionByteContract, ev.ContractID)
+	if err != nil {
+		return xdr.ContractEvent{}, errors.Join(errors.New("invalid contract ID of event"), err)
+	}
+	var contractID xdr.Hash
+	copy(contractID[:], rawID)
+
+	topics := make([]xdr.ScVal, len(ev.Topic))
+	for i, topic := range ev.Topic {
+		if err := xdr.SafeUnmarshalBase64(topic, &topics[i]); err != nil {
+			return xdr.ContractEvent{}, errors.Join(errors.New("failed to decode event topic"), err)
+		}
+	}
+	var data xdr.ScVal
+	if err := xdr.SafeUnmarshalBase64(ev.Value, &data); err != nil {
+		return xdr.ContractEvent{}, errors.Join(errors.New("failed to decode event value"), err)
+	}
+	return xdr.ContractEvent{
+		ContractId: &contractID,
+		Type:       xdr.ContractEventTypeContract,
+		Body: xdr.ContractEventBody{
+			V:  0,
+			V0: &xdr.ContractEventV0{Topics: topics, Data: data},
+		},
+	}, nil
+}
+
+// ledgerRangeRe matches the retained ledger range reported by getEvents if
+// the start ledger is out of range.
+var ledgerRangeRe = regexp.MustCompile(`oldest ledger: (\d+) and the latest ledger: (\d+)`)
+
+// ledgerRangeError classifies a getEvents error of a request starting at the
+// cursor. It reports pruned if the events after the cursor are older than
+// the retained ledger range, and future if the start ledger was not closed
+// yet.
+func ledgerRangeError(err error, cursor EventCursor) (pruned, future bool) {
+	var rpcErr *jrpc2.Error
+	if !errors.As(err, &rpcErr) {
+		return false, false
+	}
+	match := ledgerRangeRe.FindStringSubmatch(rpcErr.Message)
+	if match == nil {
+		return false, false
+	}
+	if cursor.Token != "" {
+		return true, false
+	}
+	oldest, errOldest := strconv.ParseUint(match[1], 10, 32)
+	latest, errLatest := strconv.ParseUint(match[2], 10, 32)
+	if errOldest != nil || errLatest != nil {
+		return false, false
+	}
+	return uint64(cursor.StartLedger) < oldest, uint64(cursor.StartLedger) > latest
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+	pchannel "perun.network/go-perun/channel"
+
+	"perun.network/perun-stellar-backend/client"
+	"perun.network/perun-stellar-backend/event"
+)
+
+type getEventsRequest struct {
+	StartLedger uint32 `json:"startLedger"`
+	Filters     []struct {
+		Type        string   `json:"type"`
+		ContractIDs []string `json:"contractIds"`
+	} `json:"filters"`
+	Pagination struct {
+		Cursor string `json:"cursor"`
+		Limit  uint   `json:"limit"`
+	} `json:"pagination"`
+}
+
+func rpcEvent(t *testing.T, contractID string, fn string, successful bool, token string) client.RPCEvent {
+	t.Helper()
+	perun, sym := xdr.ScSymbol("perun"), xdr.ScSymbol(fn)
+	topic0, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &perun})
+	require.NoError(t, err)
+	topic1, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym})
+	require.NoError(t, err)
+	value, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	require.NoError(t, err)
+	return client.RPCEvent{
+		Type:                     "contract",
+		ContractID:               contractID,
+		ID:                       token,
+		PagingToken:              token,
+		Topic:                    []string{topic0, topic1},
+		Value:                    value,
+		InSuccessfulContractCall: successful,
+	}
+}
+
+func TestPerunEvents(t *testing.T) {
+	cb, rpc, _ := newSubmitBackend(t, nil)
+	contractID, err := testContract.String()
+	require.NoError(t, err)
+	var requests []getEventsRequest
+	rpc.handle("getEvents", func(params json.RawMessage) interface{} {
+		var req getEventsRequest
+		require.NoError(t, json.Unmarshal(params, &req))
+		requests = append(requests, req)
+		if req.Pagination.Cursor != "" {
+			return client.RPCGetEventsResponse{LatestLedger: 11, Cursor: "0000000047244644352-0000000000"}
+		}
+		return client.RPCGetEventsResponse{
+			Events: []client.RPCEvent{
+				rpcEvent(t, contractID, "dispute", false, "0000000042949677056-0000000001"),
+				rpcEvent(t, contractID, "closed", true, "0000000042949677056-0000000002"),
+			},
+			LatestLedger: 10,
+		}
+	})
+	rpc.handle("getLatestLedger", func(json.RawMessage) interface{} {
+		return map[string]interface{}{"sequence": 9}
+	})
+
+	ctx := context.Background()
+	cursor, err := cb.LatestEventCursor(ctx)
+	require.NoError(t, err)
+	require.Equal(t, client.EventCursor{StartLedger: 10}, cursor)
+
+	evs, cursor, err := cb.PerunEvents(ctx, testContract, cursor)
+	require.NoError(t, err)
+	require.Equal(t, uint32(10), requests[0].StartLedger)
+	require.Equal(t, []string{contractID}, requests[0].Filters[0].ContractIDs)
+	// Events of failed contract calls are omitted.
+	require.Len(t, evs, 1)
+	require.Equal(t, *testContract.ContractId, *evs[0].ContractId)
+	require.Equal(t, xdr.ScSymbol("closed"), *evs[0].Body.V0.Topics[1].Sym)
+	require.Equal(t, client.EventCursor{Token: "0000000042949677056-0000000002"}, cursor)
+
+	evs, cursor, err = cb.PerunEvents(ctx, testContract, cursor)
+	require.NoError(t, err)
+	require.Empty(t, evs)
+	require.Zero(t, requests[1].StartLedger, "startLedger must not be sent with a cursor")
+	require.Equal(t, "0000000042949677056-0000000002", requests[1].Pagination.Cursor)
+	require.Equal(t, client.EventCursor{Token: "0000000047244644352-0000000000"}, cursor)
+}
+
+func TestPerunEventsPages(t *testing.T) {
+	cb, rpc, _ := newSubmitBackend(t, nil)
+	contractID, err := testContract.String()
+	require.NoError(t, err)
+	token := func(i int) string { return fmt.Sprintf("0000000042949677056-%010d", i) }
+	var cursors []string
+	rpc.handle("getEvents", func(params json.RawMessage) interface{} {
+		var req getEventsRequest
+		require.NoError(t, json.Unmarshal(params, &req))
+		cursors = append(cursors, req.Pagination.Cursor)
+		// The backlog exceeds a page, the second page is the last.
+		n := client.MaxEventsPerRequest
+		if req.Pagination.Cursor != "" {
+			n = 1
+		}
+		first := len(cursors) * client.MaxEventsPerRequest
+		resp := client.RPCGetEventsResponse{LatestLedger: 10}
+		for i := first; i < first+n; i++ {
+			resp.Events = append(resp.Events, rpcEvent(t, contractID, "closed", true, token(i)))
+		}
+		resp.Cursor = token(first + n - 1)
+		return resp
+	})
+
+	evs, next, err := cb.PerunEvents(context.Background(), testContract, client.EventCursor{StartLedger: 10})
+	require.NoError(t, err)
+	require.Len(t, evs, client.MaxEventsPerRequest+1)
+	require.Equal(t, []string{"", token(2*client.MaxEventsPerRequest - 1)}, cursors)
+	require.Equal(t, client.EventCursor{Token: token(2 * client.MaxEventsPerRequest)}, next)
+}
+
+func TestPerunEventsLedgerRange(t *testing.T) {
+	cb, rpc, _ := newSubmitBackend(t, nil)
+	rpc.handle("getEvents", func(json.RawMessage) interface{} {
+		return &jrpc2.Error{
+			Code:    jrpc2.InvalidRequest,
+			Message: "startLedger must be between the oldest ledger: 100 and the latest ledger: 200 for this rpc instance.",
+		}
+	})
+	ctx := context.Background()
+
+	_, _, err := cb.PerunEvents(ctx, testContract, client.EventCursor{StartLedger: 50})
+	require.ErrorIs(t, err, client.ErrEventsPruned)
+	_, _, err = cb.PerunEvents(ctx, testContract, client.EventCursor{Token: "0000000042949677056-0000000002"})
+	require.ErrorIs(t, err, client.ErrEventsPruned)
+
+	// No ledger was closed after the cursor yet.
+	cursor := client.EventCursor{StartLedger: 201}
+	evs, next, err := cb.PerunEvents(ctx, testContract, cursor)
+	require.NoError(t, err)
+	require.Empty(t, evs)
+	require.Equal(t, cursor, next)
+}
+
+func TestForgedPerunEventRejected(t *testing.T) {
+	cb, rpc, _ := newSubmitBackend(t, nil)
+	perun, fn := xdr.ScSymbol("perun"), xdr.ScSymbol("f_closed")
+	// The event is emitted by another contract invoked in the transaction.
+	meta, err := xdr.MarshalBase64(xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{
+		SorobanMeta: &xdr.SorobanTransactionMeta{Events: []xdr.ContractEvent{{
+			ContractId: &xdr.Hash{2},
+			Type:       xdr.ContractEventTypeContract,
+			Body: xdr.ContractEventBody{V: 0, V0: &xdr.ContractEventV0{
+				Topics: xdr.ScVec{{Type: xdr.ScValTypeScvSymbol, Sym: &perun}, {Type: xdr.ScValTypeScvSymbol, Sym: &fn}},
+				Data:   xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+			}},
+		}}, ReturnValue: xdr.ScVal{Type: xdr.ScValTypeScvVoid}},
+	}})
+	require.NoError(t, err)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+
+	err = cb.ForceClose(context.Background(), testContract, pchannel.ID{})
+	require.ErrorIs(t, err, event.ErrEventIntegrity)
+}
@@ -0,0 +1,237 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/stellar/go/txnbuild"
+)
+
+// DefaultInclusionFee is the inclusion fee offered by the default FeeStrategy.
+const DefaultInclusionFee = 100
+
+// ErrFeeExceedsMax is returned when the fee required for a transaction
+// exceeds the configured maximum.
+var ErrFeeExceedsMax = errors.New("transaction fee exceeds maximum")
+
+// FeeRequest describes the transaction a fee is chosen for.
+type FeeRequest struct {
+	// Function is the name of the invoked contract function.
+	Function string
+	// ResourceFee is the minimum resource fee returned by the simulation.
+	ResourceFee int64
+	// Attempt is zero for the first submission and increases with every
+	// resubmission of the transaction.
+	Attempt int
+	// FeeBump is set if a sponsor wraps the transaction into a fee-bump,
+	// which pays the inclusion fee once more for itself, see NewFeeBumpTx.
+	FeeBump bool
+}
+
+// Fee is the fee of a Soroban transaction, split into the inclusion fee bid
+// for the ledger and the resource fee declared in the Soroban data.
+type Fee struct {
+	Inclusion int64
+	Resource  int64
+}
+
+// Total returns the total fee of the transaction.
+func (f Fee) Total() int64 {
+	return f.Inclusion + f.Resource
+}
+
+// FeeStrategy chooses the fee of a contract call.
+type FeeStrategy interface {
+	Fee(ctx context.Context, req FeeRequest) (Fee, error)
+}
+
+// FixedFeeStrategy offers a fixed inclusion fee and adds a margin in percent
+// to the simulated resource fee.
+type FixedFeeStrategy struct {
+	InclusionFee   int64
+	ResourceMargin int64
+}
+
+// NewFixedFeeStrategy creates a new FixedFeeStrategy.
+func NewFixedFeeStrategy(inclusionFee, resourceMarginPercent int64) *FixedFeeStrategy {
+	return &FixedFeeStrategy{InclusionFee: inclusionFee, ResourceMargin: resourceMarginPercent}
+}
+
+// Fee returns the fixed inclusion fee and the resource fee with margin.
+func (s *FixedFeeStrategy) Fee(_ context.Context, req FeeRequest) (Fee, error) {
+	return Fee{
+		Inclusion: s.InclusionFee,
+		Resource:  withMargin(req.ResourceFee, s.ResourceMargin),
+	}, nil
+}
+
+// PercentileFeeStrategy bids an inclusion fee taken from the distribution of
+// Soroban inclusion fees in recent ledgers, as reported by getFeeStats.
+type PercentileFeeStrategy struct {
+	network NetworkConfig
+	// Percentile is one of 10, 20, ..., 90, 95 and 99.
+	Percentile     int
+	ResourceMargin int64
+}
+
+// NewPercentileFeeStrategy creates a new PercentileFeeStrategy.
+func NewPercentileFeeStrategy(network NetworkConfig, percentile int, resourceMarginPercent int64) *PercentileFeeStrategy {
+	return &PercentileFeeStrategy{network: network, Percentile: percentile, ResourceMargin: resourceMarginPercent}
+}
+
+// Fee returns the configured percentile of recent inclusion fees, but at
+// least the network minimum, and the resource fee with margin.
+func (s *PercentileFeeStrategy) Fee(ctx context.Context, req FeeRequest) (Fee, error) {
+	rpc := s.network.NewRPCClient()
+	defer rpc.Close()
+	stats, err := getFeeStats(ctx, rpc)
+	if err != nil {
+		return Fee{}, err
+	}
+	inclusion, err := stats.SorobanInclusionFee.percentile(s.Percentile)
+	if err != nil {
+		return Fee{}, err
+	}
+	if inclusion < txnbuild.MinBaseFee {
+		inclusion = txnbuild.MinBaseFee
+	}
+	return Fee{
+		Inclusion: inclusion,
+		Resource:  withMargin(req.ResourceFee, s.ResourceMargin),
+	}, nil
+}
+
+// EscalatingFeeStrategy increases the inclusion fee of another strategy by
+// IncreasePercent for every resubmission of a transaction.
+type EscalatingFeeStrategy struct {
+	Base            FeeStrategy
+	IncreasePercent int64
+}
+
+// NewEscalatingFeeStrategy creates a new EscalatingFeeStrategy.
+func NewEscalatingFeeStrategy(base FeeStrategy, increasePercent int64) *EscalatingFeeStrategy {
+	return &EscalatingFeeStrategy{Base: base, IncreasePercent: increasePercent}
+}
+
+// Fee returns the fee of the base strategy with the inclusion fee raised
+// according to the attempt.
+func (s *EscalatingFeeStrategy) Fee(ctx context.Context, req FeeRequest) (Fee, error) {
+	fee, err := s.Base.Fee(ctx, req)
+	if err != nil {
+		return Fee{}, err
+	}
+	for i := 0; i < req.Attempt; i++ {
+		fee.Inclusion = withMargin(fee.Inclusion, s.IncreasePercent)
+	}
+	return fee, nil
+}
+
+// CappedFeeStrategy limits the total fee of another strategy. MaxFee applies
+// to all contract functions, MaxFeePerFunction overrides it per function. A
+// limit of zero means no limit. For sponsored transactions, the limit applies
+// to the fee of the fee-bump, which includes the inclusion fee of the bump.
+type CappedFeeStrategy struct {
+	Base              FeeStrategy
+	MaxFee            int64
+	MaxFeePerFunction map[string]int64
+}
+
+// NewCappedFeeStrategy creates a new CappedFeeStrategy.
+func NewCappedFeeStrategy(base FeeStrategy, maxFee int64) *CappedFeeStrategy {
+	return &CappedFeeStrategy{Base: base, MaxFee: maxFee, MaxFeePerFunction: make(map[string]int64)}
+}
+
+// SetMaxFee sets the maximum total fee for calls of the given contract function.
+func (s *CappedFeeStrategy) SetMaxFee(function string, maxFee int64) {
+	s.MaxFeePerFunction[function] = maxFee
+}
+
+// Fee returns the fee of the base strategy. If it exceeds the maximum, the
+// inclusion fee is lowered. If the resource fee and the minimum inclusion
+// fee alone exceed the maximum, ErrFeeExceedsMax is returned.
+func (s *CappedFeeStrategy) Fee(ctx context.Context, req FeeRequest) (Fee, error) {
+	fee, err := s.Base.Fee(ctx, req)
+	if err != nil {
+		return Fee{}, err
+	}
+	maxFee := s.MaxFee
+	if limit, ok := s.MaxFeePerFunction[req.Function]; ok {
+		maxFee = limit
+	}
+	// The inclusion fee is paid by the transaction and, if sponsored, once
+	// more by the fee-bump of its single operation.
+	inclusions := int64(1)
+	if req.FeeBump {
+		inclusions = 2 //nolint:gomnd
+	}
+	if maxFee == 0 || fee.Resource+fee.Inclusion*inclusions <= maxFee {
+		return fee, nil
+	}
+	if minFee := fee.Resource + txnbuild.MinBaseFee*inclusions; minFee > maxFee {
+		return Fee{}, fmt.Errorf("%w: %s requires at least %d, maximum is %d",
+			ErrFeeExceedsMax, req.Function, minFee, maxFee)
+	}
+	fee.Inclusion = (maxFee - fee.Resource) / inclusions
+	return fee, nil
+}
+
+// withMargin adds the given margin in percent to the value.
+func withMargin(value, marginPercent int64) int64 {
+	return value + value*marginPercent/100 //nolint:gomnd
+}
+
+// RPCFeeDistribution is the distribution of fees as reported by getFeeStats.
+type RPCFeeDistribution struct {
+	Max              string `json:"max"`
+	Min              string `json:"min"`
+	Mode             string `json:"mode"`
+	P10              string `json:"p10"`
+	P20              string `json:"p20"`
+	P30              string `json:"p30"`
+	P40              string `json:"p40"`
+	P50              string `json:"p50"`
+	P60              string `json:"p60"`
+	P70              string `json:"p70"`
+	P80              string `json:"p80"`
+	P90              string `json:"p90"`
+	P95              string `json:"p95"`
+	P99              string `json:"p99"`
+	TransactionCount string `json:"transactionCount"`
+	LedgerCount      uint32 `json:"ledgerCount"`
+}
+
+// RPCGetFeeStatsResponse represents the response of getFeeStats.
+type RPCGetFeeStatsResponse struct {
+	SorobanInclusionFee RPCFeeDistribution `json:"sorobanInclusionFee"`
+	InclusionFee        RPCFeeDistribution `json:"inclusionFee"`
+	LatestLedger        uint32             `json:"latestLedger"`
+}
+
+// percentile returns the given percentile of the distribution.
+func (d RPCFeeDistribution) percentile(p int) (int64, error) {
+	values := map[int]string{
+		10: d.P10, 20: d.P20, 30: d.P30, 40: d.P40, 50: d.P50, //nolint:gomnd
+		60: d.P60, 70: d.P70, 80: d.P80, 90: d.P90, 95: d.P95, 99: d.P99, //nolint:gomnd
+	}
+	value, ok := values[p]
+	if !ok {
+		return 0, fmt.Errorf("unsupported fee percentile %d", p)
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"perun.network/perun-stellar-backend/client"
+)
+
+func TestFixedAndEscalatingFeeStrategy(t *testing.T) {
+	ctx := context.Background()
+	fixed := client.NewFixedFeeStrategy(200, 20)
+	fee, err := fixed.Fee(ctx, client.FeeRequest{Function: "close", ResourceFee: 1000})
+	require.NoError(t, err)
+	require.Equal(t, client.Fee{Inclusion: 200, Resource: 1200}, fee)
+
+	escalating := client.NewEscalatingFeeStrategy(fixed, 50)
+	fee, err = escalating.Fee(ctx, client.FeeRequest{Function: "close", ResourceFee: 1000, Attempt: 2})
+	require.NoError(t, err)
+	require.Equal(t, client.Fee{Inclusion: 450, Resource: 1200}, fee)
+}
+
+func TestCappedFeeStrategy(t *testing.T) {
+	ctx := context.Background()
+	capped := client.NewCappedFeeStrategy(client.NewFixedFeeStrategy(5000, 0), 10000)
+	capped.SetMaxFee("dispute", 0)
+	capped.SetMaxFee("fund", 1050)
+
+	fee, err := capped.Fee(ctx, client.FeeRequest{Function: "close", ResourceFee: 6000})
+	require.NoError(t, err)
+	require.Equal(t, client.Fee{Inclusion: 4000, Resource: 6000}, fee)
+
+	fee, err = capped.Fee(ctx, client.FeeRequest{Function: "dispute", ResourceFee: 6000})
+	require.NoError(t, err)
+	require.Equal(t, int64(11000), fee.Total())
+
+	_, err = capped.Fee(ctx, client.FeeRequest{Function: "fund", ResourceFee: 1000})
+	require.ErrorIs(t, err, client.ErrFeeExceedsMax)
+
+	// The fee-bump of a sponsor pays the inclusion fee once more.
+	fee, err = capped.Fee(ctx, client.FeeRequest{Function: "close", ResourceFee: 6000, FeeBump: true})
+	require.NoError(t, err)
+	require.Equal(t, client.Fee{Inclusion: 2000, Resource: 6000}, fee)
+	_, err = capped.Fee(ctx, client.FeeRequest{Function: "fund", ResourceFee: 900, FeeBump: true})
+	require.ErrorIs(t, err, client.ErrFeeExceedsMax)
+}
+
+func TestPercentileFeeStrategy(t *testing.T) {
+	rpc, url := newFakeRPC(t)
+	network := client.StandaloneNetwork()
+	network.SorobanRPCURL = url
+	rpc.handle("getFeeStats", func(json.RawMessage) interface{} {
+		return client.RPCGetFeeStatsResponse{
+			SorobanInclusionFee: client.RPCFeeDistribution{P50: "50", P90: "700"},
+		}
+	})
+
+	fee, err := client.NewPercentileFeeStrategy(network, 90, 10).Fee(context.Background(),
+		client.FeeRequest{ResourceFee: 1000})
+	require.NoError(t, err)
+	require.Equal(t, client.Fee{Inclusion: 700, Resource: 1100}, fee)
+
+	// The inclusion fee never drops below the network minimum.
+	fee, err = client.NewPercentileFeeStrategy(network, 50, 0).Fee(context.Background(),
+		client.FeeRequest{ResourceFee: 1000})
+	require.NoError(t, err)
+	require.Equal(t, int64(100), fee.Inclusion)
+
+	_, err = client.NewPercentileFeeStrategy(network, 42, 0).Fee(context.Background(), client.FeeRequest{})
+	require.Error(t, err)
+}
@@ -1,6 +1,13 @@
 package client
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/keypair"
 )
@@ -17,6 +24,81 @@ func NewHorizonClient(url string) *horizonclient.Client {
 	return &horizonclient.Client{HorizonURL: url}
 }
 
+// HorizonWithContext returns a copy of the horizon client whose requests are
+// bound to ctx, so that cancelling ctx aborts in-flight requests.
+func HorizonWithContext(ctx context.Context, hzClient *horizonclient.Client) *horizonclient.Client {
+	var base horizonclient.HTTP = http.DefaultClient
+	if hzClient.HTTP != nil {
+		base = hzClient.HTTP
+	}
+	return &horizonclient.Client{
+		HorizonURL: hzClient.HorizonURL,
+		HTTP:       contextHTTP{ctx: ctx, base: base},
+		AppName:    hzClient.AppName,
+		AppVersion: hzClient.AppVersion,
+		Headers:    hzClient.Headers,
+	}
+}
+
+// contextHTTP binds all requests of a horizon client to a context in
+// addition to the client's own request timeout.
+type contextHTTP struct {
+	ctx  context.Context //nolint:containedctx
+	base horizonclient.HTTP
+}
+
+// Do implements horizonclient.HTTP. The request is bound to the context
+// until its response body is closed.
+func (h contextHTTP) Do(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(h.ctx, cancel)
+	release := func() {
+		stop()
+		cancel()
+	}
+	resp, err := h.base.Do(req.WithContext(ctx))
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody releases the context of a request when its response body is
+// closed.
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// Close implements io.Closer.
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// Get implements horizonclient.HTTP.
+func (h contextHTTP) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(h.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return h.base.Do(req)
+}
+
+// PostForm implements horizonclient.HTTP.
+func (h contextHTTP) PostForm(url string, data url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(h.ctx, http.MethodPost, url, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return h.base.Do(req)
+}
+
 func newKeyHolder(kp *keypair.Full) keyHolder {
 	return keyHolder{kp}
 }
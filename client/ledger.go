@@ -0,0 +1,140 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stellar/go/xdr"
+	pchannel "perun.network/go-perun/channel"
+
+	"perun.network/perun-stellar-backend/wire"
+	"perun.network/perun-stellar-backend/wire/scval"
+)
+
+// MaxLedgerKeysPerRequest is the maximum number of keys soroban-rpc accepts in one getLedgerEntries call.
+const MaxLedgerKeysPerRequest = 200
+
+// channelIDVariant is the name of the enum variant the Perun contract uses as storage key.
+const channelIDVariant = "ID"
+
+// ChannelLedgerKey returns the ledger key under which the Perun contract
+// stores the channel with the given ID. The contract keeps each channel as a
+// persistent contract data entry keyed by ChannelID::ID(channel ID), which is
+// encoded as the vector [Symbol("ID"), Bytes(channel ID)].
+func ChannelLedgerKey(perunAddr xdr.ScAddress, chanID pchannel.ID) (xdr.LedgerKey, error) {
+	key, err := channelStorageKey(chanID)
+	if err != nil {
+		return xdr.LedgerKey{}, err
+	}
+	return xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract:   perunAddr,
+			Key:        key,
+			Durability: xdr.ContractDataDurabilityPersistent,
+		},
+	}, nil
+}
+
+// GetChannelsFromLedger reads the channels with the given IDs directly from
+// the ledger using getLedgerEntries, batching all IDs into as few requests as
+// possible. Channels that are not stored on the ledger are omitted from the
+// result.
+func (c *ContractBackend) GetChannelsFromLedger(ctx context.Context, perunAddr xdr.ScAddress, chanIDs []pchannel.ID) (map[pchannel.ID]wire.Channel, error) {
+	keys := make([]xdr.LedgerKey, len(chanIDs))
+	for i, id := range chanIDs {
+		key, err := ChannelLedgerKey(perunAddr, id)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+	}
+
+	rpc := c.tr.network.NewRPCClient()
+	defer rpc.Close()
+
+	channels := make(map[pchannel.ID]wire.Channel, len(chanIDs))
+	for start := 0; start < len(keys); start += MaxLedgerKeysPerRequest {
+		end := start + MaxLedgerKeysPerRequest
+		if end > len(keys) {
+			end = len(keys)
+		}
+		result, err := getLedgerEntries(ctx, rpc, keys[start:end]...)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range result.Entries {
+			id, ch, err := decodeChannelEntry(entry)
+			if err != nil {
+				return nil, err
+			}
+			channels[id] = ch
+		}
+	}
+	return channels, nil
+}
+
+// GetChannelFromLedger reads a single channel directly from the ledger. If
+// the channel is not stored in the contract, ErrChannelNotFound is returned.
+func (c *ContractBackend) GetChannelFromLedger(ctx context.Context, perunAddr xdr.ScAddress, chanID pchannel.ID) (wire.Channel, error) {
+	channels, err := c.GetChannelsFromLedger(ctx, perunAddr, []pchannel.ID{chanID})
+	if err != nil {
+		return wire.Channel{}, err
+	}
+	ch, ok := channels[chanID]
+	if !ok {
+		return wire.Channel{}, ErrChannelNotFound
+	}
+	return ch, nil
+}
+
+// channelStorageKey encodes the ChannelID::ID variant of the Perun contract.
+func channelStorageKey(chanID pchannel.ID) (xdr.ScVal, error) {
+	idBytes, err := scval.WrapScBytes(chanID[:])
+	if err != nil {
+		return xdr.ScVal{}, err
+	}
+	return scval.WrapVec(xdr.ScVec{scval.MustWrapScSymbol(channelIDVariant), idBytes})
+}
+
+// decodeChannelEntry decodes a contract data entry returned by getLedgerEntries into a channel.
+func decodeChannelEntry(entry RPCLedgerEntry) (pchannel.ID, wire.Channel, error) {
+	var data xdr.LedgerEntryData
+	if err := xdr.SafeUnmarshalBase64(entry.XDR, &data); err != nil {
+		return pchannel.ID{}, wire.Channel{}, err
+	}
+	contractData, ok := data.GetContractData()
+	if !ok {
+		return pchannel.ID{}, wire.Channel{}, errors.New("ledger entry is not contract data")
+	}
+	keyVec, ok := contractData.Key.GetVec()
+	if !ok || keyVec == nil || len(*keyVec) != 2 { //nolint:gomnd
+		return pchannel.ID{}, wire.Channel{}, errors.New("unexpected channel key")
+	}
+	keyBytes, ok := (*keyVec)[1].GetBytes()
+	if !ok || len(keyBytes) != len(pchannel.ID{}) {
+		return pchannel.ID{}, wire.Channel{}, errors.New("unexpected channel key")
+	}
+	var id pchannel.ID
+	copy(id[:], keyBytes)
+
+	var ch wire.Channel
+	if err := ch.FromScVal(contractData.Val); err != nil {
+		return pchannel.ID{}, wire.Channel{}, err
+	}
+	return id, ch, nil
+}
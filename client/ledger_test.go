@@ -0,0 +1,143 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+	pchannel "perun.network/go-perun/channel"
+	ptest "perun.network/go-perun/channel/test"
+	polytest "polycry.pt/poly-go/test"
+
+	chtest "perun.network/perun-stellar-backend/channel/test"
+	"perun.network/perun-stellar-backend/client"
+	"perun.network/perun-stellar-backend/wire"
+)
+
+const stellarBackendID = 2
+
+func randomWireChannel(t *testing.T) wire.Channel {
+	rng := polytest.Prng(t)
+	params, state := ptest.NewRandomParamsAndState(rng,
+		ptest.WithNumParts(2),
+		ptest.WithBackend(stellarBackendID),
+		ptest.WithAssets(chtest.NewRandomStellarAsset(), chtest.NewRandomStellarAsset()),
+		ptest.WithNumLocked(0),
+		ptest.WithoutApp(),
+		ptest.WithLedgerChannel(true),
+		ptest.WithVirtualChannel(false),
+	)
+	wireParams, err := wire.MakeParams(*params)
+	require.NoError(t, err)
+	wireState, err := wire.MakeState(*state)
+	require.NoError(t, err)
+	return wire.MakeChannel(wireParams, wireState, wire.Control{FundedA: true})
+}
+
+// channelKey is the key of a channel in the storage of the Perun contract,
+// the variant ChannelID::ID(channel ID) encoded as [Symbol("ID"), Bytes(id)].
+func channelKey(id pchannel.ID) xdr.ScVal {
+	variant := xdr.ScSymbol("ID")
+	idBytes := xdr.ScBytes(id[:])
+	vec := &xdr.ScVec{
+		{Type: xdr.ScValTypeScvSymbol, Sym: &variant},
+		{Type: xdr.ScValTypeScvBytes, Bytes: &idBytes},
+	}
+	return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vec}
+}
+
+func channelEntryXdr(t *testing.T, perunAddr xdr.ScAddress, ch wire.Channel) string {
+	var id pchannel.ID
+	copy(id[:], ch.State.ChannelID[:])
+	val, err := ch.ToScVal()
+	require.NoError(t, err)
+	entry := xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.ContractDataEntry{
+			Contract:   perunAddr,
+			Key:        channelKey(id),
+			Durability: xdr.ContractDataDurabilityPersistent,
+			Val:        val,
+		},
+	}
+	enc, err := xdr.MarshalBase64(entry)
+	require.NoError(t, err)
+	return enc
+}
+
+func TestChannelLedgerKey(t *testing.T) {
+	perunAddr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.Hash{1}}
+	var id pchannel.ID
+	id[0], id[31] = 0x01, 0xff
+
+	key, err := client.ChannelLedgerKey(perunAddr, id)
+	require.NoError(t, err)
+	want := xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract:   perunAddr,
+			Key:        channelKey(id),
+			Durability: xdr.ContractDataDurabilityPersistent,
+		},
+	}
+	wantXdr, err := xdr.MarshalBase64(want)
+	require.NoError(t, err)
+	keyXdr, err := xdr.MarshalBase64(key)
+	require.NoError(t, err)
+	require.Equal(t, wantXdr, keyXdr)
+}
+
+func TestGetChannelsFromLedger(t *testing.T) {
+	rpc, url := newFakeRPC(t)
+	network := client.StandaloneNetwork()
+	network.SorobanRPCURL = url
+	cfg := client.TransactorConfig{}
+	cfg.SetNetwork(network)
+	cb := client.NewContractBackend(&cfg)
+
+	perunAddr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.Hash{1}}
+	ch := randomWireChannel(t)
+	var id, missing pchannel.ID
+	copy(id[:], ch.State.ChannelID[:])
+	missing[0] = 0xff
+
+	var requested []string
+	rpc.handle("getLedgerEntries", func(params json.RawMessage) interface{} {
+		var req struct {
+			Keys []string `json:"keys"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		requested = req.Keys
+		return client.RPCGetLedgerEntriesResponse{
+			Entries: []client.RPCLedgerEntry{{XDR: channelEntryXdr(t, perunAddr, ch)}},
+		}
+	})
+
+	channels, err := cb.GetChannelsFromLedger(context.Background(), perunAddr, []pchannel.ID{id, missing})
+	require.NoError(t, err)
+	require.Len(t, requested, 2)
+	require.Len(t, channels, 1)
+	require.Equal(t, ch.State.ChannelID, channels[id].State.ChannelID)
+	require.True(t, channels[id].Control.FundedA)
+
+	got, err := cb.GetChannelInfo(context.Background(), perunAddr, id)
+	require.NoError(t, err)
+	require.Equal(t, ch.State.Version, got.State.Version)
+	require.Equal(t, 2, rpc.count("getLedgerEntries"))
+}
@@ -0,0 +1,252 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// ErrInsufficientSignatureWeight is returned when the signatures of a
+// transaction do not meet the threshold of its source account.
+var ErrInsufficientSignatureWeight = errors.New("insufficient signature weight")
+
+// SignatureCollector collects further signatures for a transaction whose
+// signatures do not yet meet the threshold of its source account, e.g. from
+// other members of a treasury. It receives the partially signed transaction
+// and its hash and returns the additional signatures.
+type SignatureCollector func(ctx context.Context, tx *txnbuild.Transaction, hash [32]byte) ([]xdr.DecoratedSignature, error)
+
+// AccountThresholds are the signature thresholds of a Stellar account and
+// the weights of its ed25519 signers, including the master key.
+type AccountThresholds struct {
+	Low     uint8
+	Medium  uint8
+	High    uint8
+	Signers map[string]uint8
+}
+
+// Weight returns the total weight of the valid signatures of the given hash.
+// Every signer is counted once.
+func (t AccountThresholds) Weight(hash [32]byte, sigs []xdr.DecoratedSignature) uint32 {
+	var weight uint32
+	for address, signerWeight := range t.Signers {
+		kp, err := keypair.ParseAddress(address)
+		if err != nil {
+			continue
+		}
+		for _, sig := range sigs {
+			if sig.Hint == kp.Hint() && kp.Verify(hash[:], sig.Signature) == nil {
+				weight += uint32(signerWeight)
+				break
+			}
+		}
+	}
+	return weight
+}
+
+// Required returns the weight required to authorize the transaction. The
+// source account must meet the low threshold for the transaction itself and
+// the threshold of each of its operations. Soroban invocations require the
+// medium threshold, footprint restorations and TTL extensions the low one.
+func (t AccountThresholds) Required(tx *txnbuild.Transaction) uint32 {
+	required := t.Low
+	for _, op := range tx.Operations() {
+		switch op.(type) {
+		case *txnbuild.RestoreFootprint, *txnbuild.ExtendFootprintTtl:
+		default:
+			required = max(required, t.Medium)
+		}
+	}
+	// A threshold of zero still requires a valid signature.
+	return max(uint32(required), 1)
+}
+
+// thresholdsFromHorizon converts the thresholds of an account loaded from Horizon.
+func thresholdsFromHorizon(acc *horizon.Account) AccountThresholds {
+	t := AccountThresholds{
+		Low:     acc.Thresholds.LowThreshold,
+		Medium:  acc.Thresholds.MedThreshold,
+		High:    acc.Thresholds.HighThreshold,
+		Signers: make(map[string]uint8, len(acc.Signers)),
+	}
+	for _, s := range acc.Signers {
+		if s.Type != "ed25519_public_key" || s.Weight <= 0 {
+			continue
+		}
+		t.Signers[s.Key] = uint8(s.Weight)
+	}
+	return t
+}
+
+// thresholdsFromEntry converts the thresholds of an account ledger entry.
+func thresholdsFromEntry(entry xdr.AccountEntry) AccountThresholds {
+	t := AccountThresholds{
+		Low:     entry.ThresholdLow(),
+		Medium:  entry.ThresholdMedium(),
+		High:    entry.ThresholdHigh(),
+		Signers: make(map[string]uint8, len(entry.Signers)+1),
+	}
+	if master := entry.MasterKeyWeight(); master > 0 {
+		t.Signers[entry.AccountId.Address()] = master
+	}
+	for _, s := range entry.Signers {
+		if s.Key.Type != xdr.SignerKeyTypeSignerKeyTypeEd25519 || s.Weight == 0 {
+			continue
+		}
+		key := s.Key
+		address, err := key.GetAddress()
+		if err != nil {
+			continue
+		}
+		t.Signers[address] = uint8(s.Weight)
+	}
+	return t
+}
+
+// LoadThresholds loads the signature thresholds of the given account, either
+// via soroban-rpc if the sender loads accounts itself or from Horizon.
+func (st *StellarSigner) LoadThresholds(ctx context.Context, address string) (AccountThresholds, error) {
+	if _, ok := st.sender.(AccountLoader); ok {
+		rpc := st.network.NewRPCClient()
+		defer rpc.Close()
+		entry, err := loadAccountEntryFromRPC(ctx, rpc, address)
+		if err != nil {
+			return AccountThresholds{}, err
+		}
+		return thresholdsFromEntry(entry), nil
+	}
+	hzAcc, err := st.getHorizonAccount(ctx, address)
+	if err != nil {
+		return AccountThresholds{}, err
+	}
+	return thresholdsFromHorizon(&hzAcc), nil
+}
+
+// isMultisig reports whether transactions of the participant's account are
+// signed by more than the participant's signer.
+func (st *StellarSigner) isMultisig() bool {
+	return len(st.coSigners) > 0 || st.collector != nil
+}
+
+// multisign signs the transaction with the participant's signer and all
+// co-signers. If their weight does not meet the threshold of the source
+// account, the remaining signatures are requested from the collector. The
+// transaction is only returned once the required weight is met.
+func (c *ContractBackend) multisign(ctx context.Context, tx *txnbuild.Transaction) (*txnbuild.Transaction, error) {
+	passphrase := c.tr.network.Passphrase
+	hash, err := tx.Hash(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	source := tx.SourceAccount().AccountID
+	thresholds, err := c.tr.LoadThresholds(ctx, source)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to load account thresholds"), err)
+	}
+	required := thresholds.Required(tx)
+
+	signers := append([]TxSigner{c.tr.signer}, c.tr.coSigners...)
+	for _, signer := range signers {
+		if signer == nil {
+			continue
+		}
+		if _, ok := thresholds.Signers[signer.Address()]; !ok {
+			continue
+		}
+		if tx, err = signTx(ctx, signer, passphrase, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	if thresholds.Weight(hash, tx.Signatures()) < required && c.tr.collector != nil {
+		sigs, err := c.tr.collector(ctx, tx, hash)
+		if err != nil {
+			return nil, errors.Join(errors.New("failed to collect signatures"), err)
+		}
+		if tx, err = tx.AddSignatureDecorated(sigs...); err != nil {
+			return nil, err
+		}
+	}
+
+	if weight := thresholds.Weight(hash, tx.Signatures()); weight < required {
+		return nil, fmt.Errorf("%w: %s has weight %d, requires %d", ErrInsufficientSignatureWeight, source, weight, required)
+	}
+	return tx, nil
+}
+
+// multisigSend returns a signSendFunc that submits transactions once they
+// carry the signature weight required by the source account.
+func (c *ContractBackend) multisigSend() (signSendFunc, error) {
+	if c.tr.sponsor != nil {
+		return c.sponsoredSend(c.multisign)
+	}
+	sender, ok := c.tr.sender.(SignedSender)
+	if !ok {
+		return nil, ErrNoSignedSender
+	}
+	return func(ctx context.Context, txUnsigned txnbuild.Transaction) (string, xdr.TransactionMeta, error) {
+		hash, err := txUnsigned.HashHex(c.tr.network.Passphrase)
+		if err != nil {
+			return "", xdr.TransactionMeta{}, err
+		}
+		tx, err := c.multisign(ctx, &txUnsigned)
+		if err != nil {
+			return hash, xdr.TransactionMeta{}, err
+		}
+		txMeta, err := sender.SendSignedTx(ctx, tx)
+		return hash, txMeta, err
+	}, nil
+}
+
+// authSigners returns the signers of the account among the participant's
+// signer and the co-signers. The Soroban host requires the signatures of an
+// authorization entry to meet the medium threshold of the account. The
+// SignatureCollector only collects transaction signatures, so the signers
+// have to meet the threshold on their own.
+func (c *ContractBackend) authSigners(ctx context.Context, account string) ([]TxSigner, error) {
+	thresholds, err := c.tr.LoadThresholds(ctx, account)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to load account thresholds"), err)
+	}
+	var (
+		signers []TxSigner
+		weight  uint32
+	)
+	seen := make(map[string]bool)
+	for _, signer := range append([]TxSigner{c.tr.signer}, c.tr.coSigners...) {
+		if signer == nil || seen[signer.Address()] {
+			continue
+		}
+		signerWeight, ok := thresholds.Signers[signer.Address()]
+		if !ok {
+			continue
+		}
+		seen[signer.Address()] = true
+		signers = append(signers, signer)
+		weight += uint32(signerWeight)
+	}
+	if required := max(uint32(thresholds.Medium), 1); weight < required {
+		return nil, fmt.Errorf("%w: signers of authorization entries of %s have weight %d, requires %d",
+			ErrInsufficientSignatureWeight, account, weight, required)
+	}
+	return signers, nil
+}
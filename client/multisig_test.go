@@ -0,0 +1,208 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/perun-stellar-backend/client"
+)
+
+// treasuryAccountXdr encodes a 2-of-3 account: the master key and both
+// co-signers have weight 1, all thresholds are 2.
+func treasuryAccountXdr(t *testing.T, master keypair.KP, coSigners ...keypair.KP) string {
+	signers := make([]xdr.Signer, len(coSigners))
+	for i, kp := range coSigners {
+		signers[i] = xdr.Signer{Key: xdr.MustSigner(kp.Address()), Weight: 1}
+	}
+	entry := xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeAccount,
+		Account: &xdr.AccountEntry{
+			AccountId:  xdr.MustAddress(master.Address()),
+			SeqNum:     41,
+			Thresholds: xdr.Thresholds{1, 2, 2, 2},
+			Signers:    signers,
+		},
+	}
+	enc, err := xdr.MarshalBase64(entry)
+	require.NoError(t, err)
+	return enc
+}
+
+// newTreasuryBackend sets up a ContractBackend for a 2-of-3 account against a
+// fake soroban-rpc. It returns the transactions that were submitted.
+func newTreasuryBackend(t *testing.T, master, signerA, signerB *keypair.Full, configure func(*client.TransactorConfig),
+) (*client.ContractBackend, *[]*txnbuild.Transaction) {
+	rpc, url := newFakeRPC(t)
+	net := client.StandaloneNetwork()
+	net.SorobanRPCURL = url
+	account := treasuryAccountXdr(t, master, signerA, signerB)
+	rpc.handle("getLedgerEntries", func(json.RawMessage) interface{} {
+		return client.RPCGetLedgerEntriesResponse{Entries: []client.RPCLedgerEntry{{XDR: account}}}
+	})
+	txData, err := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+	require.NoError(t, err)
+	retXdr, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	require.NoError(t, err)
+	rpc.handle("simulateTransaction", func(json.RawMessage) interface{} {
+		return client.RPCSimulateTxResponse{
+			TransactionData: txData,
+			Results:         []client.RPCSimulateHostFunctionResult{{XDR: retXdr}},
+		}
+	})
+	var submitted []*txnbuild.Transaction
+	rpc.handle("sendTransaction", func(params json.RawMessage) interface{} {
+		var req struct {
+			Transaction string `json:"transaction"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		generic, err := txnbuild.TransactionFromXDR(req.Transaction)
+		require.NoError(t, err)
+		tx, ok := generic.Transaction()
+		require.True(t, ok)
+		submitted = append(submitted, tx)
+		return client.RPCSendTxResponse{Status: client.TxStatusPending}
+	})
+	meta := successMeta(t)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+	rpc.handle("getLatestLedger", func(json.RawMessage) interface{} {
+		return map[string]interface{}{"sequence": 900}
+	})
+
+	sender := client.NewRPCSender(nil, net)
+	sender.SetPolling(time.Millisecond, 5)
+	cfg := client.TransactorConfig{}
+	cfg.SetKeyPair(master)
+	cfg.SetNetwork(net)
+	cfg.SetSender(sender)
+	cfg.SetRetryPolicy(fastRetryPolicy())
+	configure(&cfg)
+	return client.NewContractBackend(&cfg), &submitted
+}
+
+func TestMultisigCoSigners(t *testing.T) {
+	master, signerA, signerB := keypair.MustRandom(), keypair.MustRandom(), keypair.MustRandom()
+	cb, submitted := newTreasuryBackend(t, master, signerA, signerB, func(cfg *client.TransactorConfig) {
+		cfg.SetCoSigners(client.NewKeypairSigner(signerA))
+	})
+
+	_, err := cb.InvokeSignedTx(context.Background(), "close", xdr.ScVec{}, testContract)
+	require.NoError(t, err)
+	require.Len(t, *submitted, 1)
+	tx := (*submitted)[0]
+	hash, err := tx.Hash(client.StandaloneNetwork().Passphrase)
+	require.NoError(t, err)
+	require.Len(t, tx.Signatures(), 2)
+	require.NoError(t, master.Verify(hash[:], tx.Signatures()[0].Signature))
+	require.NoError(t, signerA.Verify(hash[:], tx.Signatures()[1].Signature))
+}
+
+func TestMultisigSignatureCollector(t *testing.T) {
+	master, signerA, signerB := keypair.MustRandom(), keypair.MustRandom(), keypair.MustRandom()
+	collected := 0
+	cb, submitted := newTreasuryBackend(t, master, signerA, signerB, func(cfg *client.TransactorConfig) {
+		cfg.SetSignatureCollector(func(_ context.Context, tx *txnbuild.Transaction, hash [32]byte) ([]xdr.DecoratedSignature, error) {
+			collected++
+			require.Len(t, tx.Signatures(), 1)
+			sig, err := signerB.SignDecorated(hash[:])
+			return []xdr.DecoratedSignature{sig}, err
+		})
+	})
+
+	_, err := cb.InvokeSignedTx(context.Background(), "close", xdr.ScVec{}, testContract)
+	require.NoError(t, err)
+	require.Equal(t, 1, collected)
+	require.Len(t, *submitted, 1)
+	require.Len(t, (*submitted)[0].Signatures(), 2)
+}
+
+func TestMultisigInsufficientWeight(t *testing.T) {
+	master, signerA, signerB := keypair.MustRandom(), keypair.MustRandom(), keypair.MustRandom()
+	cb, submitted := newTreasuryBackend(t, master, signerA, signerB, func(cfg *client.TransactorConfig) {
+		// A signer that is not a signer of the account does not add weight.
+		cfg.SetCoSigners(client.NewKeypairSigner(keypair.MustRandom()))
+	})
+
+	_, err := cb.InvokeSignedTx(context.Background(), "close", xdr.ScVec{}, testContract)
+	require.ErrorIs(t, err, client.ErrInsufficientSignatureWeight)
+	require.Empty(t, *submitted)
+}
+
+func TestMultisigAuthEntries(t *testing.T) {
+	master, signerA, signerB := keypair.MustRandom(), keypair.MustRandom(), keypair.MustRandom()
+	cb, _ := newTreasuryBackend(t, master, signerA, signerB, func(cfg *client.TransactorConfig) {
+		cfg.SetCoSigners(client.NewKeypairSigner(signerA))
+	})
+
+	signed, err := cb.AuthorizeEntries(context.Background(), []xdr.SorobanAuthorizationEntry{addressAuthEntry(t, master, testContract)})
+	require.NoError(t, err)
+	creds := signed[0].Credentials.MustAddress()
+	payload, err := xdr.HashIdPreimage{
+		Type: xdr.EnvelopeTypeEnvelopeTypeSorobanAuthorization,
+		SorobanAuthorization: &xdr.HashIdPreimageSorobanAuthorization{
+			NetworkId:                 network.ID(client.StandaloneNetwork().Passphrase),
+			Nonce:                     creds.Nonce,
+			SignatureExpirationLedger: creds.SignatureExpirationLedger,
+			Invocation:                signed[0].RootInvocation,
+		},
+	}.MarshalBinary()
+	require.NoError(t, err)
+	hash := sha256.Sum256(payload)
+
+	// The host requires the signatures ordered by public key.
+	sigs := *creds.Signature.MustVec()
+	require.Len(t, sigs, 2)
+	signers := map[string]*keypair.Full{}
+	for _, kp := range []*keypair.Full{master, signerA} {
+		pubKey := xdr.MustAddress(kp.Address()).Ed25519
+		signers[string(pubKey[:])] = kp
+	}
+	var prev []byte
+	for _, sig := range sigs {
+		sigMap := *sig.MustMap()
+		pubKey, sigBytes := sigMap[0].Val.MustBytes(), sigMap[1].Val.MustBytes()
+		require.Negative(t, bytes.Compare(prev, pubKey))
+		prev = pubKey
+		kp, ok := signers[string(pubKey)]
+		require.True(t, ok)
+		require.NoError(t, kp.Verify(hash[:], sigBytes))
+	}
+}
+
+func TestMultisigAuthEntriesInsufficientWeight(t *testing.T) {
+	master, signerA, signerB := keypair.MustRandom(), keypair.MustRandom(), keypair.MustRandom()
+	cb, _ := newTreasuryBackend(t, master, signerA, signerB, func(cfg *client.TransactorConfig) {
+		// Collected signatures cannot authorize entries.
+		cfg.SetSignatureCollector(func(context.Context, *txnbuild.Transaction, [32]byte) ([]xdr.DecoratedSignature, error) {
+			return nil, nil
+		})
+	})
+
+	_, err := cb.AuthorizeEntries(context.Background(), []xdr.SorobanAuthorizationEntry{addressAuthEntry(t, master, testContract)})
+	require.ErrorIs(t, err, client.ErrInsufficientSignatureWeight)
+}
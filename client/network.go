@@ -0,0 +1,142 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/jhttp"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/network"
+)
+
+const (
+	SorobanRPCURL          = "http://localhost:8000/soroban/rpc"
+	SorobanRPCURLTestNet   = "https://soroban-testnet.stellar.org"
+	HorizonURLFutureNet    = "https://horizon-futurenet.stellar.org"
+	SorobanRPCURLFutureNet = "https://rpc-futurenet.stellar.org"
+	HorizonURLPubNet       = "https://horizon.stellar.org"
+)
+
+// ErrUnknownNetwork is returned if a Horizon URL does not belong to any of the
+// network presets. The network has to be configured explicitly then.
+var ErrUnknownNetwork = errors.New("horizon URL matches no known network, set the network explicitly")
+
+// NetworkConfig describes the Stellar network a client talks to: the Horizon
+// and Soroban RPC endpoints, the network passphrase used for signing and
+// optional HTTP headers attached to every request (e.g. API keys).
+type NetworkConfig struct {
+	HorizonURL    string
+	SorobanRPCURL string
+	Passphrase    string
+	Headers       map[string]string
+}
+
+// StandaloneNetwork returns the configuration of a local standalone network
+// as started by quickstart.sh.
+func StandaloneNetwork() NetworkConfig {
+	return NetworkConfig{
+		HorizonURL:    HorizonURL,
+		SorobanRPCURL: SorobanRPCURL,
+		Passphrase:    NETWORK_PASSPHRASE,
+	}
+}
+
+// TestNetwork returns the configuration of the SDF test network.
+func TestNetwork() NetworkConfig {
+	return NetworkConfig{
+		HorizonURL:    HorizonURLTestNet,
+		SorobanRPCURL: SorobanRPCURLTestNet,
+		Passphrase:    NETWORK_PASSPHRASETestNet,
+	}
+}
+
+// FutureNetwork returns the configuration of the SDF future network.
+func FutureNetwork() NetworkConfig {
+	return NetworkConfig{
+		HorizonURL:    HorizonURLFutureNet,
+		SorobanRPCURL: SorobanRPCURLFutureNet,
+		Passphrase:    network.FutureNetworkPassphrase,
+	}
+}
+
+// PublicNetwork returns the configuration of the public Stellar network.
+// There is no public Soroban RPC endpoint operated by SDF, so SorobanRPCURL
+// is left empty and has to be set to the operator's own node or provider.
+func PublicNetwork() NetworkConfig {
+	return NetworkConfig{
+		HorizonURL: HorizonURLPubNet,
+		Passphrase: network.PublicNetworkPassphrase,
+	}
+}
+
+// NetworkFromHorizonURL returns the preset whose Horizon URL matches url. If
+// no preset matches, ErrUnknownNetwork is returned, since the passphrase and
+// the Soroban RPC endpoint of the network cannot be derived from the URL.
+func NetworkFromHorizonURL(url string) (NetworkConfig, error) {
+	for _, preset := range []NetworkConfig{StandaloneNetwork(), TestNetwork(), FutureNetwork(), PublicNetwork()} {
+		if sameURL(preset.HorizonURL, url) {
+			return preset, nil
+		}
+	}
+	return NetworkConfig{}, fmt.Errorf("%w: %s", ErrUnknownNetwork, url)
+}
+
+// IsZero reports whether the configuration is unset.
+func (n NetworkConfig) IsZero() bool {
+	return n.HorizonURL == "" && n.SorobanRPCURL == "" && n.Passphrase == ""
+}
+
+// NewHorizonClient creates a horizon client for the network.
+func (n NetworkConfig) NewHorizonClient() *horizonclient.Client {
+	hzClient := NewHorizonClient(n.HorizonURL)
+	hzClient.Headers = n.Headers
+	return hzClient
+}
+
+// NewRPCClient creates a JSON-RPC client for the network's Soroban RPC endpoint.
+func (n NetworkConfig) NewRPCClient() *jrpc2.Client {
+	var opts *jhttp.ChannelOptions
+	if len(n.Headers) > 0 {
+		opts = &jhttp.ChannelOptions{Client: &http.Client{
+			Transport: headerTransport{headers: n.Headers, base: http.DefaultTransport},
+		}}
+	}
+	ch := jhttp.NewChannel(n.SorobanRPCURL, opts)
+	return jrpc2.NewClient(ch, nil)
+}
+
+// headerTransport attaches a fixed set of headers to every request.
+type headerTransport struct {
+	headers map[string]string
+	base    http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	return t.base.RoundTrip(req)
+}
+
+func sameURL(a, b string) bool {
+	return strings.TrimRight(a, "/") == strings.TrimRight(b, "/")
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"perun.network/perun-stellar-backend/client"
+)
+
+func TestNetworkFromHorizonURL(t *testing.T) {
+	for url, want := range map[string]client.NetworkConfig{
+		"http://localhost:8000/":       client.StandaloneNetwork(),
+		client.HorizonURLTestNet:       client.TestNetwork(),
+		"https://horizon.stellar.org/": client.PublicNetwork(),
+	} {
+		network, err := client.NetworkFromHorizonURL(url)
+		require.NoError(t, err)
+		require.Equal(t, want, network)
+	}
+
+	_, err := client.NetworkFromHorizonURL("https://horizon.example.org")
+	require.ErrorIs(t, err, client.ErrUnknownNetwork)
+	var cfg client.TransactorConfig
+	require.ErrorIs(t, cfg.SetHorizonURL("https://horizon.example.org"), client.ErrUnknownNetwork)
+}
+
+func TestNetworkConfigHeaders(t *testing.T) {
+	var gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("X-Api-Key")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"sequence":7}}`))
+	}))
+	defer srv.Close()
+
+	network := client.StandaloneNetwork()
+	network.SorobanRPCURL = srv.URL
+	network.Headers = map[string]string{"X-Api-Key": "secret"}
+
+	rpc := network.NewRPCClient()
+	defer rpc.Close()
+	var res struct {
+		Sequence uint32 `json:"sequence"`
+	}
+	require.NoError(t, rpc.CallResult(context.Background(), "getLatestLedger", nil, &res))
+	require.Equal(t, uint32(7), res.Sequence)
+	require.Equal(t, "secret", gotKey)
+}
+
+func TestHorizonWithContext(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.HorizonWithContext(ctx, client.NewHorizonClient(srv.URL)).Root()
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stellar/go/xdr"
+)
+
+// AuthorizeFunc signs the authorization entries of a contract invocation
+// that require address credentials.
+type AuthorizeFunc func(ctx context.Context, entries []xdr.SorobanAuthorizationEntry) ([]xdr.SorobanAuthorizationEntry, error)
+
+// Relayer submits contract invocations on behalf of participants. The
+// relayer is the source of the transaction and pays its fees, while the
+// participant only authorizes the invocation through Soroban authorization
+// entries with address credentials.
+type Relayer interface {
+	// Relay simulates the invocation with the relayer as source, lets
+	// authorize sign the returned authorization entries, simulates again for
+	// the final footprint and submits the transaction.
+	Relay(ctx context.Context, fname string, args xdr.ScVec, contract xdr.ScAddress, authorize AuthorizeFunc) (xdr.TransactionMeta, error)
+	// ExtendTTL extends the time-to-live of the ledger entries with the
+	// relayer as source. It requires no authorization of the participant.
+	ExtendTTL(ctx context.Context, keys []xdr.LedgerKey, extendTo uint32) error
+}
+
+// LocalRelayer is a Relayer submitting from the account of a ContractBackend.
+type LocalRelayer struct {
+	cb *ContractBackend
+}
+
+var _ Relayer = (*LocalRelayer)(nil)
+
+// NewLocalRelayer creates a new LocalRelayer that submits invocations with
+// the account, signer and sender of the given ContractBackend.
+func NewLocalRelayer(cb *ContractBackend) *LocalRelayer {
+	return &LocalRelayer{cb: cb}
+}
+
+// Relay submits the invocation from the relayer's account.
+func (r *LocalRelayer) Relay(ctx context.Context, fname string, args xdr.ScVec, contract xdr.ScAddress, authorize AuthorizeFunc,
+) (xdr.TransactionMeta, error) {
+	send, err := r.cb.defaultSend()
+	if err != nil {
+		return xdr.TransactionMeta{}, err
+	}
+	return r.cb.invokeAuthorized(ctx, r.cb.tr.seq, send, fname, args, contract, authorize)
+}
+
+// ExtendTTL extends the time-to-live of the ledger entries from the
+// relayer's account.
+func (r *LocalRelayer) ExtendTTL(ctx context.Context, keys []xdr.LedgerKey, extendTo uint32) error {
+	return r.cb.ExtendTTL(ctx, keys, extendTo)
+}
+
+// AuthorizeEntries signs the authorization entries with address credentials
+// of the participant. The nonce of each entry is chosen by the simulation,
+// the signature expires DefaultAuthValidityLedgers after the latest ledger.
+// Entries of multi-signature accounts are signed by the participant's signer
+// and the co-signers, like transactions. Entries for other addresses result
+// in ErrForeignAuthEntry.
+func (c *ContractBackend) AuthorizeEntries(ctx context.Context, entries []xdr.SorobanAuthorizationEntry,
+) ([]xdr.SorobanAuthorizationEntry, error) {
+	if c.tr.signer == nil {
+		return nil, errors.New("authorization requires the signer of the participant")
+	}
+	account := c.tr.signer.Address()
+	signers := []TxSigner{c.tr.signer}
+	if c.tr.isMultisig() && hasAddressCredentials(entries) {
+		var err error
+		if signers, err = c.authSigners(ctx, account); err != nil {
+			return nil, err
+		}
+	}
+	rpc := c.tr.network.NewRPCClient()
+	defer rpc.Close()
+	latest, err := getLatestLedger(ctx, rpc)
+	if err != nil {
+		return nil, err
+	}
+	return signAuthEntries(ctx, entries, account, signers, c.tr.network.Passphrase, latest+DefaultAuthValidityLedgers)
+}
+
+// invokeAuthorized invokes a contract function from the account of seq. If
+// the simulation returns authorization entries with address credentials,
+// they are signed by authorize and the invocation is simulated again, so
+// that the footprint and resource fee cover the signature verification.
+func (c *ContractBackend) invokeAuthorized(ctx context.Context, seq *SequenceManager, send signSendFunc,
+	fname string, args xdr.ScVec, contract xdr.ScAddress, authorize AuthorizeFunc,
+) (xdr.TransactionMeta, error) {
+	acc, err := seq.Account(ctx)
+	if err != nil {
+		return xdr.TransactionMeta{}, errors.Join(errors.New("failed to load source account"), err)
+	}
+
+	invokeHostFunctionOp := BuildContractCallOp(acc, xdr.ScSymbol(fname), args, contract)
+	preFlightOp, minFee, err := c.preflightRestoring(ctx, seq, send, acc, *invokeHostFunctionOp)
+	if err != nil {
+		return xdr.TransactionMeta{}, err
+	}
+	if hasAddressCredentials(preFlightOp.Auth) {
+		invokeHostFunctionOp.Auth, err = authorize(ctx, preFlightOp.Auth)
+		if err != nil {
+			return xdr.TransactionMeta{}, errors.Join(errors.New("failed to authorize invocation"), err)
+		}
+		preFlightOp, minFee, err = PreflightHostFunctions(ctx, c.tr.syncHorizonClient(), c.tr.network, acc, *invokeHostFunctionOp)
+		if err != nil {
+			return xdr.TransactionMeta{}, err
+		}
+	}
+
+	feeReq := FeeRequest{Function: fname, ResourceFee: minFee}
+	return c.submit(ctx, seq, send, feeReq, &preFlightOp)
+}
+
+// hasAddressCredentials reports whether any of the entries has to be signed.
+func hasAddressCredentials(entries []xdr.SorobanAuthorizationEntry) bool {
+	for _, entry := range entries {
+		if entry.Credentials.Type == xdr.SorobanCredentialsTypeSorobanCredentialsAddress {
+			return true
+		}
+	}
+	return false
+}
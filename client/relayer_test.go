@@ -0,0 +1,132 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/perun-stellar-backend/client"
+)
+
+func TestRelayerSubmitsAuthorizedInvocations(t *testing.T) {
+	participant, relayer := keypair.MustRandom(), keypair.MustRandom()
+
+	rpc, url := newFakeRPC(t)
+	net := client.StandaloneNetwork()
+	net.SorobanRPCURL = url
+
+	rpc.handle("getLedgerEntries", func(params json.RawMessage) interface{} {
+		var req struct {
+			Keys []string `json:"keys"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		var key xdr.LedgerKey
+		require.NoError(t, xdr.SafeUnmarshalBase64(req.Keys[0], &key))
+		require.Equal(t, relayer.Address(), key.Account.AccountId.Address(), "only the relayer account is needed")
+		return client.RPCGetLedgerEntriesResponse{
+			Entries: []client.RPCLedgerEntry{{XDR: accountEntryXdr(t, relayer, 10)}},
+		}
+	})
+	rpc.handle("getLatestLedger", func(json.RawMessage) interface{} {
+		return map[string]uint32{"sequence": 7}
+	})
+	txData, err := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+	require.NoError(t, err)
+	retXdr, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	require.NoError(t, err)
+	authXdr, err := xdr.MarshalBase64(addressAuthEntry(t, participant, testContract))
+	require.NoError(t, err)
+	rpc.handle("simulateTransaction", func(params json.RawMessage) interface{} {
+		var req struct {
+			Transaction string `json:"transaction"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		generic, err := txnbuild.TransactionFromXDR(req.Transaction)
+		require.NoError(t, err)
+		tx, ok := generic.Transaction()
+		require.True(t, ok)
+		require.Equal(t, relayer.Address(), tx.SourceAccount().AccountID)
+		return client.RPCSimulateTxResponse{
+			TransactionData: txData,
+			Results:         []client.RPCSimulateHostFunctionResult{{XDR: retXdr, Auth: []string{authXdr}}},
+			MinResourceFee:  int64(100 * rpc.count("simulateTransaction")),
+		}
+	})
+	var submitted *txnbuild.Transaction
+	rpc.handle("sendTransaction", func(params json.RawMessage) interface{} {
+		var req struct {
+			Transaction string `json:"transaction"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		generic, err := txnbuild.TransactionFromXDR(req.Transaction)
+		require.NoError(t, err)
+		var ok bool
+		submitted, ok = generic.Transaction()
+		require.True(t, ok)
+		return client.RPCSendTxResponse{Status: client.TxStatusPending}
+	})
+	meta := successMeta(t)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+
+	newBackend := func(kp *keypair.Full, configure func(*client.TransactorConfig)) *client.ContractBackend {
+		sender := client.NewRPCSender(nil, net)
+		sender.SetPolling(time.Millisecond, 5)
+		cfg := client.TransactorConfig{}
+		cfg.SetKeyPair(kp)
+		cfg.SetNetwork(net)
+		cfg.SetSender(sender)
+		configure(&cfg)
+		return client.NewContractBackend(&cfg)
+	}
+	relayerCB := newBackend(relayer, func(*client.TransactorConfig) {})
+	cb := newBackend(participant, func(cfg *client.TransactorConfig) {
+		cfg.SetRelayer(client.NewLocalRelayer(relayerCB))
+	})
+
+	_, err = cb.InvokeSignedTx(context.Background(), "dispute", xdr.ScVec{}, testContract)
+	require.NoError(t, err)
+	require.Equal(t, 2, rpc.count("simulateTransaction"), "must re-simulate with signed entries")
+	require.NotNil(t, submitted)
+	require.Equal(t, relayer.Address(), submitted.SourceAccount().AccountID)
+	// The fee covers the second simulation.
+	require.Equal(t, int64(client.DefaultInclusionFee+200), submitted.BaseFee())
+	hash, err := submitted.Hash(net.Passphrase)
+	require.NoError(t, err)
+	require.NoError(t, relayer.Verify(hash[:], submitted.Signatures()[0].Signature))
+
+	auth := submitted.Operations()[0].(*txnbuild.InvokeHostFunction).Auth
+	require.Len(t, auth, 1)
+	verifyAuthEntry(t, auth[0], participant, net.Passphrase)
+	creds := auth[0].Credentials.MustAddress()
+	require.Equal(t, xdr.Int64(42), creds.Nonce)
+	require.Equal(t, xdr.Uint32(7+client.DefaultAuthValidityLedgers), creds.SignatureExpirationLedger)
+
+	// The participant's channels are kept alive by the relayer as well.
+	key, err := client.ChannelLedgerKey(testContract, [32]byte{1})
+	require.NoError(t, err)
+	require.NoError(t, cb.ExtendTTL(context.Background(), []xdr.LedgerKey{key}, 1000))
+	require.Equal(t, relayer.Address(), submitted.SourceAccount().AccountID)
+	require.IsType(t, &txnbuild.ExtendFootprintTtl{}, submitted.Operations()[0])
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+// Paths of the remote signer protocol.
+const (
+	SignerAddressPath   = "/address"
+	SignerTxHashPath    = "/sign/tx"
+	SignerAuthEntryPath = "/sign/auth"
+)
+
+// ErrRemoteSigner is returned when the remote signer refused or failed to sign.
+var ErrRemoteSigner = errors.New("remote signer error")
+
+// SignerAddressResponse is the response of the address endpoint.
+type SignerAddressResponse struct {
+	Address string `json:"address"`
+}
+
+// SignTxHashRequest is the request of the transaction signing endpoint.
+type SignTxHashRequest struct {
+	// Hash is the hex encoded transaction hash.
+	Hash string `json:"hash"`
+}
+
+// SignAuthEntryRequest is the request of the authorization signing endpoint.
+type SignAuthEntryRequest struct {
+	// Preimage is the base64 encoded XDR of the HashIdPreimage.
+	Preimage string `json:"preimage"`
+}
+
+// SignatureResponse is the response of the signing endpoints.
+type SignatureResponse struct {
+	// Signature is the base64 encoded ed25519 signature.
+	Signature string `json:"signature"`
+}
+
+// RemoteSigner is a TxSigner that delegates signing to a signing service
+// over HTTP. The service exposes the following JSON endpoints:
+//   - GET /address returns {"address": "G..."}.
+//   - POST /sign/tx with {"hash": "<hex>"} returns {"signature": "<base64>"}.
+//   - POST /sign/auth with {"preimage": "<base64 XDR>"} returns
+//     {"signature": "<base64>"}.
+//
+// Errors are reported with a non-200 status code and the reason as body.
+// Every returned signature is verified against the address of the service.
+type RemoteSigner struct {
+	url     string
+	client  *http.Client
+	address string
+	kp      *keypair.FromAddress
+}
+
+var _ TxSigner = (*RemoteSigner)(nil)
+
+// NewRemoteSigner connects to the signing service at the given URL and
+// queries the address it signs for. If client is nil, http.DefaultClient is used.
+func NewRemoteSigner(ctx context.Context, url string, client *http.Client) (*RemoteSigner, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	s := &RemoteSigner{url: strings.TrimSuffix(url, "/"), client: client}
+	var res SignerAddressResponse
+	if err := s.call(ctx, http.MethodGet, SignerAddressPath, nil, &res); err != nil {
+		return nil, err
+	}
+	kp, err := keypair.ParseAddress(res.Address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid address %q", ErrRemoteSigner, res.Address)
+	}
+	s.address, s.kp = res.Address, kp
+	return s, nil
+}
+
+// Address returns the address of the remote account.
+func (s *RemoteSigner) Address() string {
+	return s.address
+}
+
+// SignTxHash requests a signature of the transaction hash.
+func (s *RemoteSigner) SignTxHash(ctx context.Context, hash [32]byte) ([]byte, error) {
+	return s.sign(ctx, SignerTxHashPath, SignTxHashRequest{Hash: hex.EncodeToString(hash[:])}, hash)
+}
+
+// SignAuthEntry requests a signature of the authorization preimage. The
+// preimage is sent in full, so that the service can check what it authorizes.
+func (s *RemoteSigner) SignAuthEntry(ctx context.Context, preimage xdr.HashIdPreimage) ([]byte, error) {
+	payload, err := preimage.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(payload)
+	return s.sign(ctx, SignerAuthEntryPath, SignAuthEntryRequest{Preimage: encoded}, sha256.Sum256(payload))
+}
+
+// sign requests a signature of the signed hash and verifies it against the
+// address of the signer.
+func (s *RemoteSigner) sign(ctx context.Context, path string, req interface{}, signed [32]byte) ([]byte, error) {
+	var res SignatureResponse
+	if err := s.call(ctx, http.MethodPost, path, req, &res); err != nil {
+		return nil, err
+	}
+	sig, err := base64.StdEncoding.DecodeString(res.Signature)
+	if err != nil {
+		return nil, errors.Join(ErrRemoteSigner, err)
+	}
+	if err := s.kp.Verify(signed[:], sig); err != nil {
+		return nil, fmt.Errorf("%w: invalid signature for %s: %w", ErrRemoteSigner, s.address, err)
+	}
+	return sig, nil
+}
+
+func (s *RemoteSigner) call(ctx context.Context, method, path string, req, res interface{}) error {
+	var body io.Reader
+	if req != nil {
+		encoded, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(encoded)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, s.url+path, body)
+	if err != nil {
+		return err
+	}
+	if req != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpRes, err := s.client.Do(httpReq)
+	if err != nil {
+		return errors.Join(ErrRemoteSigner, err)
+	}
+	defer httpRes.Body.Close()
+	if httpRes.StatusCode != http.StatusOK {
+		reason, _ := io.ReadAll(io.LimitReader(httpRes.Body, 1024)) //nolint:gomnd
+		return fmt.Errorf("%w: %s: %s", ErrRemoteSigner, httpRes.Status, strings.TrimSpace(string(reason)))
+	}
+	if err := json.NewDecoder(httpRes.Body).Decode(res); err != nil {
+		return errors.Join(ErrRemoteSigner, err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// restoreFunction is the name under which footprint restorations are passed
+// to the FeeStrategy.
+const restoreFunction = "restore_footprint"
+
+// ErrRestoreRequired is returned by the simulation of an invocation whose
+// footprint contains archived ledger entries.
+var ErrRestoreRequired = errors.New("archived ledger entries must be restored")
+
+// RPCRestorePreamble is returned by simulateTransaction if archived ledger
+// entries must be restored before the transaction can be executed.
+type RPCRestorePreamble struct {
+	TransactionData string `json:"transactionData"`
+	MinResourceFee  int64  `json:"minResourceFee,string"`
+}
+
+// RestoreRequiredError carries the footprint and the resource fee of the
+// RestoreFootprint operation required before an invocation.
+type RestoreRequiredError struct {
+	TransactionData xdr.SorobanTransactionData
+	MinResourceFee  int64
+}
+
+func (e *RestoreRequiredError) Error() string {
+	return fmt.Sprintf("%v: %d entries", ErrRestoreRequired, len(e.TransactionData.Resources.Footprint.ReadWrite))
+}
+
+func (e *RestoreRequiredError) Unwrap() error {
+	return ErrRestoreRequired
+}
+
+// restoreRequiredError decodes a restore preamble into a RestoreRequiredError.
+func restoreRequiredError(preamble RPCRestorePreamble) error {
+	var data xdr.SorobanTransactionData
+	if err := xdr.SafeUnmarshalBase64(preamble.TransactionData, &data); err != nil {
+		return errors.Join(errors.New("failed to decode restore preamble"), err)
+	}
+	return &RestoreRequiredError{TransactionData: data, MinResourceFee: preamble.MinResourceFee}
+}
+
+// preflightRestoring simulates the invocation. If ledger entries of its
+// footprint are archived, they are restored by a RestoreFootprint transaction
+// sent from the same source and the invocation is simulated again.
+func (c *ContractBackend) preflightRestoring(ctx context.Context, seq *SequenceManager, send signSendFunc,
+	acc txnbuild.Account, op txnbuild.InvokeHostFunction,
+) (txnbuild.InvokeHostFunction, int64, error) {
+	preFlightOp, minFee, err := PreflightHostFunctions(ctx, c.tr.syncHorizonClient(), c.tr.network, acc, op)
+	var restoreErr *RestoreRequiredError
+	if !errors.As(err, &restoreErr) {
+		return preFlightOp, minFee, err
+	}
+
+	restoreOp := &txnbuild.RestoreFootprint{
+		SourceAccount: acc.GetAccountID(),
+		Ext: xdr.TransactionExt{
+			V:           1,
+			SorobanData: &restoreErr.TransactionData,
+		},
+	}
+	feeReq := FeeRequest{Function: restoreFunction, ResourceFee: restoreErr.MinResourceFee}
+	if _, err := c.submit(ctx, seq, send, feeReq, restoreOp); err != nil {
+		return txnbuild.InvokeHostFunction{}, 0, errors.Join(errors.New("failed to restore footprint"), err)
+	}
+
+	acc, err = seq.Account(ctx)
+	if err != nil {
+		return txnbuild.InvokeHostFunction{}, 0, errors.Join(errors.New("failed to load source account"), err)
+	}
+	return PreflightHostFunctions(ctx, c.tr.syncHorizonClient(), c.tr.network, acc, op)
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/perun-stellar-backend/client"
+)
+
+func TestInvokeRestoresArchivedFootprint(t *testing.T) {
+	cb, rpc, _ := newSubmitBackend(t, nil)
+
+	archived := xdr.LedgerKey{
+		Type:         xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{Contract: testContract, Key: xdr.ScVal{Type: xdr.ScValTypeScvVoid}, Durability: xdr.ContractDataDurabilityPersistent},
+	}
+	restoreData, err := xdr.MarshalBase64(xdr.SorobanTransactionData{
+		Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{ReadWrite: []xdr.LedgerKey{archived}}},
+	})
+	require.NoError(t, err)
+	txData, err := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+	require.NoError(t, err)
+	retXdr, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	require.NoError(t, err)
+	rpc.handle("simulateTransaction", func(json.RawMessage) interface{} {
+		res := client.RPCSimulateTxResponse{
+			TransactionData: txData,
+			Results:         []client.RPCSimulateHostFunctionResult{{XDR: retXdr}},
+			MinResourceFee:  1000,
+		}
+		if rpc.count("simulateTransaction") == 1 {
+			res.RestorePreamble = &client.RPCRestorePreamble{TransactionData: restoreData, MinResourceFee: 500}
+		}
+		return res
+	})
+	var (
+		ops  []txnbuild.Operation
+		data []*xdr.SorobanTransactionData
+	)
+	rpc.handle("sendTransaction", func(params json.RawMessage) interface{} {
+		var req struct {
+			Transaction string `json:"transaction"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		generic, err := txnbuild.TransactionFromXDR(req.Transaction)
+		require.NoError(t, err)
+		tx, ok := generic.Transaction()
+		require.True(t, ok)
+		ops = append(ops, tx.Operations()...)
+		data = append(data, tx.ToXDR().V1.Tx.Ext.SorobanData)
+		return client.RPCSendTxResponse{Status: client.TxStatusPending}
+	})
+	meta := successMeta(t)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+
+	_, err = cb.InvokeSignedTx(context.Background(), "withdraw", xdr.ScVec{}, testContract)
+	require.NoError(t, err)
+	require.Equal(t, 2, rpc.count("simulateTransaction"))
+	require.Len(t, ops, 2)
+	restore, ok := ops[0].(*txnbuild.RestoreFootprint)
+	require.True(t, ok, "first transaction must restore the footprint")
+	require.NotNil(t, restore)
+	require.NotNil(t, data[0])
+	require.Equal(t, []xdr.LedgerKey{archived}, data[0].Resources.Footprint.ReadWrite)
+	require.Equal(t, xdr.Int64(500), data[0].ResourceFee)
+	_, ok = ops[1].(*txnbuild.InvokeHostFunction)
+	require.True(t, ok, "second transaction must be the invocation")
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// Status values returned by the Soroban RPC methods sendTransaction and getTransaction.
+const (
+	TxStatusPending       = "PENDING"
+	TxStatusDuplicate     = "DUPLICATE"
+	TxStatusTryAgainLater = "TRY_AGAIN_LATER"
+	TxStatusError         = "ERROR"
+	TxStatusSuccess       = "SUCCESS"
+	TxStatusNotFound      = "NOT_FOUND"
+	TxStatusFailed        = "FAILED"
+)
+
+var (
+	// ErrAccountNotFound is returned when an account does not exist on the ledger.
+	ErrAccountNotFound = errors.New("account not found on ledger")
+	// ErrTxFailed is returned when a submitted transaction failed on-chain.
+	ErrTxFailed = errors.New("transaction failed")
+)
+
+// RPCLedgerEntry represents a single entry of the getLedgerEntries response.
+type RPCLedgerEntry struct {
+	Key                string `json:"key"`
+	XDR                string `json:"xdr"`
+	LastModifiedLedger uint32 `json:"lastModifiedLedgerSeq"`
+	LiveUntilLedgerSeq uint32 `json:"liveUntilLedgerSeq,omitempty"`
+}
+
+// RPCGetLedgerEntriesResponse represents the type of the RPCGetLedgerEntriesResponse.
+type RPCGetLedgerEntriesResponse struct {
+	Entries      []RPCLedgerEntry `json:"entries"`
+	LatestLedger uint32           `json:"latestLedger"`
+}
+
+// RPCSendTxResponse represents the type of the RPCSendTxResponse.
+type RPCSendTxResponse struct {
+	Status         string `json:"status"`
+	Hash           string `json:"hash"`
+	LatestLedger   uint32 `json:"latestLedger"`
+	ErrorResultXdr string `json:"errorResultXdr,omitempty"`
+}
+
+// getLedgerEntries fetches the given ledger keys in a single getLedgerEntries call.
+func getLedgerEntries(ctx context.Context, rpc *jrpc2.Client, keys ...xdr.LedgerKey) (RPCGetLedgerEntriesResponse, error) {
+	encodedKeys := make([]string, len(keys))
+	for i, key := range keys {
+		encoded, err := xdr.MarshalBase64(key)
+		if err != nil {
+			return RPCGetLedgerEntriesResponse{}, err
+		}
+		encodedKeys[i] = encoded
+	}
+	result := RPCGetLedgerEntriesResponse{}
+	err := rpc.CallResult(ctx, "getLedgerEntries", struct {
+		Keys []string `json:"keys"`
+	}{encodedKeys}, &result)
+	if err != nil {
+		return RPCGetLedgerEntriesResponse{}, err
+	}
+	return result, nil
+}
+
+// loadAccountFromRPC loads the current sequence number of an account via getLedgerEntries.
+func loadAccountFromRPC(ctx context.Context, rpc *jrpc2.Client, address string) (*txnbuild.SimpleAccount, error) {
+	accountEntry, err := loadAccountEntryFromRPC(ctx, rpc, address)
+	if err != nil {
+		return nil, err
+	}
+	return &txnbuild.SimpleAccount{AccountID: address, Sequence: int64(accountEntry.SeqNum)}, nil
+}
+
+// loadAccountEntryFromRPC loads the ledger entry of an account via getLedgerEntries.
+func loadAccountEntryFromRPC(ctx context.Context, rpc *jrpc2.Client, address string) (xdr.AccountEntry, error) {
+	accountID, err := xdr.AddressToAccountId(address)
+	if err != nil {
+		return xdr.AccountEntry{}, err
+	}
+	key := xdr.LedgerKey{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.LedgerKeyAccount{AccountId: accountID},
+	}
+	result, err := getLedgerEntries(ctx, rpc, key)
+	if err != nil {
+		return xdr.AccountEntry{}, err
+	}
+	if len(result.Entries) == 0 {
+		return xdr.AccountEntry{}, ErrAccountNotFound
+	}
+	var data xdr.LedgerEntryData
+	if err := xdr.SafeUnmarshalBase64(result.Entries[0].XDR, &data); err != nil {
+		return xdr.AccountEntry{}, err
+	}
+	accountEntry, ok := data.GetAccount()
+	if !ok {
+		return xdr.AccountEntry{}, errors.New("ledger entry is not an account")
+	}
+	return accountEntry, nil
+}
+
+// sendTransaction submits a signed transaction envelope via sendTransaction.
+func sendTransaction(ctx context.Context, rpc *jrpc2.Client, txBase64 string) (RPCSendTxResponse, error) {
+	result := RPCSendTxResponse{}
+	err := rpc.CallResult(ctx, "sendTransaction", struct {
+		Transaction string `json:"transaction"`
+	}{txBase64}, &result)
+	if err != nil {
+		return RPCSendTxResponse{}, err
+	}
+	return result, nil
+}
+
+// getLatestLedger returns the sequence of the latest ledger known to soroban-rpc.
+func getLatestLedger(ctx context.Context, rpc *jrpc2.Client) (uint32, error) {
+	result := struct {
+		Sequence uint32 `json:"sequence"`
+	}{}
+	err := rpc.CallResult(ctx, "getLatestLedger", nil, &result)
+	if err != nil {
+		return 0, err
+	}
+	return result.Sequence, nil
+}
+
+// getFeeStats returns the fee distribution of recent ledgers.
+func getFeeStats(ctx context.Context, rpc *jrpc2.Client) (RPCGetFeeStatsResponse, error) {
+	result := RPCGetFeeStatsResponse{}
+	err := rpc.CallResult(ctx, "getFeeStats", nil, &result)
+	if err != nil {
+		return RPCGetFeeStatsResponse{}, err
+	}
+	return result, nil
+}
+
+// getTransaction queries the status of a transaction via getTransaction.
+func getTransaction(ctx context.Context, rpc *jrpc2.Client, hash string) (RPCGetTxResponse, error) {
+	result := RPCGetTxResponse{}
+	err := rpc.CallResult(ctx, "getTransaction", struct {
+		Hash string `json:"hash"`
+	}{hash}, &result)
+	if err != nil {
+		return RPCGetTxResponse{}, err
+	}
+	return result, nil
+}
+
+// decodeTxResult decodes the result meta of a finished transaction.
+func decodeTxResult(result RPCGetTxResponse) (xdr.TransactionMeta, error) {
+	if result.Status == TxStatusFailed {
+		return xdr.TransactionMeta{}, failedTxError(result)
+	}
+	var transactionMeta xdr.TransactionMeta
+	err := xdr.SafeUnmarshalBase64(result.ResultMetaXdr, &transactionMeta)
+	if err != nil {
+		return xdr.TransactionMeta{}, err
+	}
+	return transactionMeta, nil
+}
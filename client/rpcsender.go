@@ -0,0 +1,172 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	// DefaultTxPollInterval is the interval in which getTransaction is polled.
+	DefaultTxPollInterval = time.Second
+	// DefaultTxPollAttempts is the number of getTransaction polls before giving up.
+	DefaultTxPollAttempts = 30
+)
+
+// ErrTxNotConfirmed is returned when a transaction was not confirmed in time.
+var ErrTxNotConfirmed = errors.New("transaction not confirmed in time")
+
+// AccountLoader is implemented by senders that load the source account
+// themselves. A ContractBackend using such a sender does not contact Horizon.
+type AccountLoader interface {
+	LoadAccount(ctx context.Context, address string) (txnbuild.Account, error)
+}
+
+// RPCSender implements the Sender interface using Soroban RPC only. It loads
+// accounts with getLedgerEntries, submits with sendTransaction and polls
+// getTransaction until the transaction succeeded or failed.
+type RPCSender struct {
+	signer       TxSigner
+	network      NetworkConfig
+	pollInterval time.Duration
+	pollAttempts int
+}
+
+var (
+	_ Sender        = (*RPCSender)(nil)
+	_ SignedSender  = (*RPCSender)(nil)
+	_ FeeBumpSender = (*RPCSender)(nil)
+	_ AccountLoader = (*RPCSender)(nil)
+)
+
+// NewRPCSender creates a new RPCSender. When used in a TransactorConfig, the
+// signer and network of the config take precedence.
+func NewRPCSender(kp *keypair.Full, network NetworkConfig) *RPCSender {
+	var signer TxSigner
+	if kp != nil {
+		signer = NewKeypairSigner(kp)
+	}
+	return &RPCSender{
+		signer:       signer,
+		network:      network,
+		pollInterval: DefaultTxPollInterval,
+		pollAttempts: DefaultTxPollAttempts,
+	}
+}
+
+// SetNetwork sets the network of the sender.
+func (s *RPCSender) SetNetwork(network NetworkConfig) {
+	s.network = network
+}
+
+// SetSigner sets the signer of the transactions.
+func (s *RPCSender) SetSigner(signer TxSigner) {
+	s.signer = signer
+}
+
+// SetPolling sets the interval and number of attempts used to poll for the transaction result.
+func (s *RPCSender) SetPolling(interval time.Duration, attempts int) {
+	s.pollInterval = interval
+	s.pollAttempts = attempts
+}
+
+// SetHzClient is a no-op, the RPCSender does not use Horizon.
+func (s *RPCSender) SetHzClient(*horizonclient.Client) {}
+
+// LoadAccount loads the account and its current sequence number via getLedgerEntries.
+func (s *RPCSender) LoadAccount(ctx context.Context, address string) (txnbuild.Account, error) {
+	rpc := s.network.NewRPCClient()
+	defer rpc.Close()
+	return loadAccountFromRPC(ctx, rpc, address)
+}
+
+// SignSendTx signs the transaction, submits it via sendTransaction and waits
+// until it is included in a ledger.
+func (s *RPCSender) SignSendTx(ctx context.Context, txUnsigned txnbuild.Transaction) (xdr.TransactionMeta, error) {
+	tx, err := signTx(ctx, s.signer, s.network.Passphrase, &txUnsigned)
+	if err != nil {
+		return xdr.TransactionMeta{}, err
+	}
+	return s.SendSignedTx(ctx, tx)
+}
+
+// SendSignedTx submits an already signed transaction via sendTransaction and
+// waits until it is included in a ledger.
+func (s *RPCSender) SendSignedTx(ctx context.Context, tx *txnbuild.Transaction) (xdr.TransactionMeta, error) {
+	txBase64, err := tx.Base64()
+	if err != nil {
+		return xdr.TransactionMeta{}, err
+	}
+	return s.submit(ctx, txBase64)
+}
+
+// SendFeeBumpTx submits a signed fee-bump transaction via sendTransaction and
+// waits until it is included in a ledger.
+func (s *RPCSender) SendFeeBumpTx(ctx context.Context, tx *txnbuild.FeeBumpTransaction) (xdr.TransactionMeta, error) {
+	txBase64, err := tx.Base64()
+	if err != nil {
+		return xdr.TransactionMeta{}, err
+	}
+	return s.submit(ctx, txBase64)
+}
+
+// submit sends the base64 encoded transaction envelope and waits for its result.
+func (s *RPCSender) submit(ctx context.Context, txBase64 string) (xdr.TransactionMeta, error) {
+	rpc := s.network.NewRPCClient()
+	defer rpc.Close()
+
+	sent, err := sendTransaction(ctx, rpc, txBase64)
+	if err != nil {
+		return xdr.TransactionMeta{}, err
+	}
+	switch sent.Status {
+	case TxStatusPending, TxStatusDuplicate:
+	case TxStatusTryAgainLater:
+		return xdr.TransactionMeta{}, ErrTryAgainLater
+	case TxStatusError:
+		return xdr.TransactionMeta{}, classifyResultXdr(sent.ErrorResultXdr)
+	default:
+		return xdr.TransactionMeta{}, fmt.Errorf("sendTransaction returned %s: %s", sent.Status, sent.ErrorResultXdr)
+	}
+
+	return s.waitForTx(ctx, rpc, sent.Hash)
+}
+
+func (s *RPCSender) waitForTx(ctx context.Context, rpc *jrpc2.Client, hash string) (xdr.TransactionMeta, error) {
+	for i := 0; i < s.pollAttempts; i++ {
+		result, err := getTransaction(ctx, rpc, hash)
+		if err != nil {
+			return xdr.TransactionMeta{}, err
+		}
+		if result.Status != TxStatusNotFound {
+			return decodeTxResult(result)
+		}
+		select {
+		case <-ctx.Done():
+			return xdr.TransactionMeta{}, ctx.Err()
+		case <-time.After(s.pollInterval):
+		}
+	}
+	return xdr.TransactionMeta{}, ErrTxNotConfirmed
+}
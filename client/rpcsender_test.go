@@ -0,0 +1,193 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/perun-stellar-backend/client"
+)
+
+// fakeRPC is a minimal Soroban RPC server answering with canned results.
+type fakeRPC struct {
+	mu       sync.Mutex
+	handlers map[string]func(params json.RawMessage) interface{}
+	calls    map[string]int
+}
+
+func newFakeRPC(t *testing.T) (*fakeRPC, string) {
+	f := &fakeRPC{
+		handlers: make(map[string]func(json.RawMessage) interface{}),
+		calls:    make(map[string]int),
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		f.mu.Lock()
+		f.calls[req.Method]++
+		handler, ok := f.handlers[req.Method]
+		f.mu.Unlock()
+		require.True(t, ok, "unexpected method %s", req.Method)
+		w.Header().Set("Content-Type", "application/json")
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		// Handlers answer with an error by returning a *jrpc2.Error.
+		if result := handler(req.Params); isRPCError(result) {
+			resp["error"] = result
+		} else {
+			resp["result"] = result
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(srv.Close)
+	return f, srv.URL
+}
+
+func isRPCError(result interface{}) bool {
+	_, ok := result.(*jrpc2.Error)
+	return ok
+}
+
+func (f *fakeRPC) handle(method string, handler func(params json.RawMessage) interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[method] = handler
+}
+
+func (f *fakeRPC) count(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+func accountEntryXdr(t *testing.T, kp keypair.KP, seq int64) string {
+	entry := xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeAccount,
+		Account: &xdr.AccountEntry{
+			AccountId: xdr.MustAddress(kp.Address()),
+			SeqNum:    xdr.SequenceNumber(seq),
+		},
+	}
+	enc, err := xdr.MarshalBase64(entry)
+	require.NoError(t, err)
+	return enc
+}
+
+func TestRPCSender(t *testing.T) {
+	kp := keypair.MustRandom()
+	rpc, url := newFakeRPC(t)
+	network := client.StandaloneNetwork()
+	network.SorobanRPCURL = url
+
+	rpc.handle("getLedgerEntries", func(json.RawMessage) interface{} {
+		return client.RPCGetLedgerEntriesResponse{
+			Entries:      []client.RPCLedgerEntry{{XDR: accountEntryXdr(t, kp, 41)}},
+			LatestLedger: 10,
+		}
+	})
+	rpc.handle("sendTransaction", func(json.RawMessage) interface{} {
+		return client.RPCSendTxResponse{Status: client.TxStatusPending, Hash: "abc"}
+	})
+	meta, err := xdr.MarshalBase64(xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{}})
+	require.NoError(t, err)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		if rpc.count("getTransaction") < 2 {
+			return client.RPCGetTxResponse{Status: client.TxStatusNotFound}
+		}
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+
+	sender := client.NewRPCSender(kp, network)
+	sender.SetPolling(time.Millisecond, 5)
+
+	acc, err := sender.LoadAccount(context.Background(), kp.Address())
+	require.NoError(t, err)
+	seq, err := acc.GetSequenceNumber()
+	require.NoError(t, err)
+	require.Equal(t, int64(41), seq)
+
+	tx, err := txnbuild.NewTransaction(client.GetBaseTransactionParamsWithFee(acc, txnbuild.MinBaseFee,
+		&txnbuild.BumpSequence{BumpTo: 0}))
+	require.NoError(t, err)
+	txMeta, err := sender.SignSendTx(context.Background(), *tx)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), txMeta.V)
+	require.Equal(t, 2, rpc.count("getTransaction"))
+}
+
+func TestRPCSenderFailedTx(t *testing.T) {
+	kp := keypair.MustRandom()
+	rpc, url := newFakeRPC(t)
+	network := client.StandaloneNetwork()
+	network.SorobanRPCURL = url
+
+	rpc.handle("sendTransaction", func(json.RawMessage) interface{} {
+		return client.RPCSendTxResponse{Status: client.TxStatusPending, Hash: "abc"}
+	})
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusFailed}
+	})
+
+	sender := client.NewRPCSender(kp, network)
+	sender.SetPolling(time.Millisecond, 5)
+
+	acc := &txnbuild.SimpleAccount{AccountID: kp.Address(), Sequence: 1}
+	tx, err := txnbuild.NewTransaction(client.GetBaseTransactionParamsWithFee(acc, txnbuild.MinBaseFee,
+		&txnbuild.BumpSequence{BumpTo: 0}))
+	require.NoError(t, err)
+	_, err = sender.SignSendTx(context.Background(), *tx)
+	require.ErrorIs(t, err, client.ErrTxFailed)
+}
+
+func TestRPCSenderCancelled(t *testing.T) {
+	kp := keypair.MustRandom()
+	rpc, url := newFakeRPC(t)
+	network := client.StandaloneNetwork()
+	network.SorobanRPCURL = url
+
+	rpc.handle("sendTransaction", func(json.RawMessage) interface{} {
+		return client.RPCSendTxResponse{Status: client.TxStatusPending, Hash: "abc"}
+	})
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusNotFound}
+	})
+
+	sender := client.NewRPCSender(kp, network)
+	sender.SetPolling(time.Hour, 5)
+
+	acc := &txnbuild.SimpleAccount{AccountID: kp.Address(), Sequence: 1}
+	tx, err := txnbuild.NewTransaction(client.GetBaseTransactionParamsWithFee(acc, txnbuild.MinBaseFee,
+		&txnbuild.BumpSequence{BumpTo: 0}))
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = sender.SignSendTx(ctx, *tx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
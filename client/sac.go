@@ -0,0 +1,210 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	pchannel "perun.network/go-perun/channel"
+
+	"perun.network/perun-stellar-backend/channel/types"
+)
+
+// createAssetContractFunction is the name under which deployments of Stellar
+// Asset Contracts are passed to the FeeStrategy.
+const createAssetContractFunction = "create_asset_contract"
+
+var (
+	// ErrTrustlineMissing is returned if an account has no trustline to a
+	// classic asset of a channel.
+	ErrTrustlineMissing = errors.New("trustline missing")
+	// ErrTrustlineNotAuthorized is returned if the issuer of a classic asset
+	// of a channel did not authorize the trustline of an account.
+	ErrTrustlineNotAuthorized = errors.New("trustline not authorized")
+)
+
+// ContractInstanceLedgerKey returns the ledger key of the instance of the
+// contract.
+func ContractInstanceLedgerKey(contract xdr.ScAddress) xdr.LedgerKey {
+	return xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract:   contract,
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+			Durability: xdr.ContractDataDurabilityPersistent,
+		},
+	}
+}
+
+// BuildCreateAssetContractOp builds the operation deploying the Stellar Asset
+// Contract of the classic asset.
+func BuildCreateAssetContractOp(sourceAccount string, asset xdr.Asset) *txnbuild.InvokeHostFunction {
+	return &txnbuild.InvokeHostFunction{
+		HostFunction: xdr.HostFunction{
+			Type: xdr.HostFunctionTypeHostFunctionTypeCreateContract,
+			CreateContract: &xdr.CreateContractArgs{
+				ContractIdPreimage: xdr.ContractIdPreimage{
+					Type:      xdr.ContractIdPreimageTypeContractIdPreimageFromAsset,
+					FromAsset: &asset,
+				},
+				Executable: xdr.ContractExecutable{
+					Type: xdr.ContractExecutableTypeContractExecutableStellarAsset,
+				},
+			},
+		},
+		SourceAccount: sourceAccount,
+	}
+}
+
+// IsDeployed reports whether an instance of the contract exists on the ledger.
+func (d *Deployer) IsDeployed(ctx context.Context, contract xdr.ScAddress) (bool, error) {
+	rpc := d.cb.tr.network.NewRPCClient()
+	defer rpc.Close()
+	result, err := getLedgerEntries(ctx, rpc, ContractInstanceLedgerKey(contract))
+	if err != nil {
+		return false, err
+	}
+	return len(result.Entries) > 0, nil
+}
+
+// DeployStellarAssetContract deploys the Stellar Asset Contract of the
+// classic asset, unless it is already deployed, and returns the channel
+// asset of the contract. Deployed reports whether the contract was deployed.
+func (d *Deployer) DeployStellarAssetContract(ctx context.Context, asset txnbuild.Asset) (sac *types.StellarAsset, deployed bool, err error) {
+	sac, err = types.NewStellarAssetFromClassic(asset, d.cb.tr.network.Passphrase)
+	if err != nil {
+		return nil, false, err
+	}
+	addr, err := sac.MakeScAddress()
+	if err != nil {
+		return nil, false, err
+	}
+	exists, err := d.IsDeployed(ctx, addr)
+	if err != nil {
+		return nil, false, errors.Join(errors.New("failed to look up contract instance"), err)
+	}
+	if exists {
+		return sac, false, nil
+	}
+
+	source, err := d.cb.tr.GetAddress()
+	if err != nil {
+		return nil, false, err
+	}
+	classic, _ := sac.ClassicAsset()
+	if err := d.invoke(ctx, createAssetContractFunction, BuildCreateAssetContractOp(source, classic)); err != nil {
+		return nil, false, fmt.Errorf("deploying Stellar Asset Contract of %s: %w", classic.StringCanonical(), err)
+	}
+	return sac, true, nil
+}
+
+// ClassicAsset returns the classic asset wrapped by the contract of the
+// asset. If the asset does not carry it, the name of the contract is queried
+// and matched against the contract ID. It reports false if the contract is
+// not a Stellar Asset Contract.
+func (c *ContractBackend) ClassicAsset(ctx context.Context, asset *types.StellarAsset) (xdr.Asset, bool, error) {
+	if classic, ok := asset.ClassicAsset(); ok {
+		return classic, true, nil
+	}
+	addr, err := asset.MakeScAddress()
+	if err != nil {
+		return xdr.Asset{}, false, err
+	}
+	name, err := NewTokenClient(c, addr).Name(ctx)
+	if err != nil {
+		return xdr.Asset{}, false, err
+	}
+	classic, err := asset.MatchClassicAsset(name, c.tr.network.Passphrase)
+	if err != nil {
+		return xdr.Asset{}, false, nil //nolint:nilerr // The contract is a custom token.
+	}
+	return classic, true, nil
+}
+
+// CheckTrustlines checks that the participant's account can hold the classic
+// assets wrapped by the Stellar Asset Contracts among the given assets. It
+// returns ErrTrustlineMissing or ErrTrustlineNotAuthorized otherwise. Native
+// XLM, custom tokens and assets issued by the account itself need no
+// trustline.
+func (c *ContractBackend) CheckTrustlines(ctx context.Context, assets []pchannel.Asset) error {
+	address, err := c.tr.GetAddress()
+	if err != nil {
+		return err
+	}
+	accountID, err := xdr.AddressToAccountId(address)
+	if err != nil {
+		return errors.Join(errors.New("invalid account address"), err)
+	}
+
+	var (
+		keys    []xdr.LedgerKey
+		classic []xdr.Asset
+	)
+	for _, asset := range assets {
+		stellarAsset, ok := asset.(*types.StellarAsset)
+		if !ok {
+			continue
+		}
+		a, isClassic, err := c.ClassicAsset(ctx, stellarAsset)
+		if err != nil {
+			return err
+		}
+		if !isClassic || a.Type == xdr.AssetTypeAssetTypeNative || a.GetIssuer() == address {
+			continue
+		}
+		var key xdr.LedgerKey
+		if err := key.SetTrustline(accountID, a.ToTrustLineAsset()); err != nil {
+			return err
+		}
+		keys = append(keys, key)
+		classic = append(classic, a)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	rpc := c.tr.network.NewRPCClient()
+	defer rpc.Close()
+	result, err := getLedgerEntries(ctx, rpc, keys...)
+	if err != nil {
+		return err
+	}
+	trustlines := make(map[string]xdr.TrustLineEntry, len(result.Entries))
+	for _, entry := range result.Entries {
+		var data xdr.LedgerEntryData
+		if err := xdr.SafeUnmarshalBase64(entry.XDR, &data); err != nil {
+			return errors.Join(errors.New("failed to decode trustline"), err)
+		}
+		trustline, ok := data.GetTrustLine()
+		if !ok {
+			return errors.New("ledger entry is not a trustline")
+		}
+		trustlines[trustline.Asset.ToAsset().StringCanonical()] = trustline
+	}
+	for _, a := range classic {
+		trustline, ok := trustlines[a.StringCanonical()]
+		if !ok {
+			return fmt.Errorf("%w: %s for account %s", ErrTrustlineMissing, a.StringCanonical(), address)
+		}
+		if !xdr.TrustLineFlags(trustline.Flags).IsAuthorized() {
+			return fmt.Errorf("%w: %s for account %s", ErrTrustlineNotAuthorized, a.StringCanonical(), address)
+		}
+	}
+	return nil
+}
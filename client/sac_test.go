@@ -0,0 +1,167 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+	pchannel "perun.network/go-perun/channel"
+
+	chtest "perun.network/perun-stellar-backend/channel/test"
+	"perun.network/perun-stellar-backend/channel/types"
+	"perun.network/perun-stellar-backend/client"
+)
+
+// handleLedgerEntries answers getLedgerEntries with an account entry for
+// account keys and the given entries for all other keys.
+func handleLedgerEntries(t *testing.T, rpc *fakeRPC, entries map[string]string) {
+	t.Helper()
+	account := accountEntryXdr(t, keypair.MustRandom(), 41)
+	rpc.handle("getLedgerEntries", func(params json.RawMessage) interface{} {
+		var req struct {
+			Keys []string `json:"keys"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		var res client.RPCGetLedgerEntriesResponse
+		for _, encoded := range req.Keys {
+			var key xdr.LedgerKey
+			require.NoError(t, xdr.SafeUnmarshalBase64(encoded, &key))
+			if key.Type == xdr.LedgerEntryTypeAccount {
+				res.Entries = append(res.Entries, client.RPCLedgerEntry{Key: encoded, XDR: account})
+			} else if entry, ok := entries[encoded]; ok {
+				res.Entries = append(res.Entries, client.RPCLedgerEntry{Key: encoded, XDR: entry})
+			}
+		}
+		return res
+	})
+}
+
+// trustline returns the encoded ledger key and entry of the account's
+// trustline to the asset.
+func trustline(t *testing.T, account string, asset txnbuild.Asset, flags xdr.TrustLineFlags) (string, string) {
+	t.Helper()
+	xdrAsset, err := asset.ToXDR()
+	require.NoError(t, err)
+	accountID := xdr.MustAddress(account)
+	var key xdr.LedgerKey
+	require.NoError(t, key.SetTrustline(accountID, xdrAsset.ToTrustLineAsset()))
+	encodedKey, err := xdr.MarshalBase64(key)
+	require.NoError(t, err)
+	entry, err := xdr.MarshalBase64(xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeTrustline,
+		TrustLine: &xdr.TrustLineEntry{
+			AccountId: accountID,
+			Asset:     xdrAsset.ToTrustLineAsset(),
+			Limit:     1_000_000,
+			Flags:     xdr.Uint32(flags),
+		},
+	})
+	require.NoError(t, err)
+	return encodedKey, entry
+}
+
+func TestCheckTrustlines(t *testing.T) {
+	cb, rpc, _ := newSubmitBackend(t, nil)
+	account, err := cb.GetTransactor().GetAddress()
+	require.NoError(t, err)
+	passphrase := client.StandaloneNetwork().Passphrase
+	issuer := keypair.MustRandom().Address()
+	usdc := txnbuild.CreditAsset{Code: "USDC", Issuer: issuer}
+	eurc := txnbuild.CreditAsset{Code: "EURC", Issuer: issuer}
+	own := txnbuild.CreditAsset{Code: "OWN", Issuer: account}
+
+	usdcKey, usdcEntry := trustline(t, account, usdc, xdr.TrustLineFlagsAuthorizedFlag)
+	eurcKey, eurcEntry := trustline(t, account, eurc, 0)
+	entries := map[string]string{usdcKey: usdcEntry}
+	handleLedgerEntries(t, rpc, entries)
+
+	assets := func(classic ...txnbuild.Asset) []pchannel.Asset {
+		var res []pchannel.Asset
+		for _, a := range classic {
+			asset, err := types.NewStellarAssetFromClassic(a, passphrase)
+			require.NoError(t, err)
+			res = append(res, asset)
+		}
+		return res
+	}
+	ctx := context.Background()
+
+	require.NoError(t, cb.CheckTrustlines(ctx, assets(txnbuild.NativeAsset{}, usdc, own)))
+	require.ErrorIs(t, cb.CheckTrustlines(ctx, assets(usdc, eurc)), client.ErrTrustlineMissing)
+	entries[eurcKey] = eurcEntry
+	require.ErrorIs(t, cb.CheckTrustlines(ctx, assets(usdc, eurc)), client.ErrTrustlineNotAuthorized)
+}
+
+func TestCheckTrustlinesQueriesContractName(t *testing.T) {
+	cb, rpc, _ := newSubmitBackend(t, nil)
+	passphrase := client.StandaloneNetwork().Passphrase
+	usdc := txnbuild.CreditAsset{Code: "USDC", Issuer: keypair.MustRandom().Address()}
+	handleLedgerEntries(t, rpc, nil)
+	name := xdr.ScString("USDC:" + usdc.Issuer)
+	handleTokenCalls(t, rpc, map[string]xdr.ScVal{"name": {Type: xdr.ScValTypeScvString, Str: &name}})
+
+	// Assets received from the other party only carry the contract ID.
+	sac, err := types.NewStellarAssetFromClassic(usdc, passphrase)
+	require.NoError(t, err)
+	decoded := types.NewStellarAsset(sac.Asset.ContractID())
+	classic, ok, err := cb.ClassicAsset(context.Background(), decoded)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "USDC:"+usdc.Issuer, classic.StringCanonical())
+	require.ErrorIs(t, cb.CheckTrustlines(context.Background(), []pchannel.Asset{decoded}), client.ErrTrustlineMissing)
+
+	// Custom tokens need no trustline.
+	custom := chtest.NewRandomStellarAsset()
+	_, ok, err = cb.ClassicAsset(context.Background(), custom)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NoError(t, cb.CheckTrustlines(context.Background(), []pchannel.Asset{custom}))
+}
+
+func TestDeployStellarAssetContract(t *testing.T) {
+	cb, rpc, submitted := newSubmitBackend(t, nil)
+	meta := successMeta(t)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+	entries := make(map[string]string)
+	handleLedgerEntries(t, rpc, entries)
+	deployer := client.NewDeployer(cb)
+	usdc := txnbuild.CreditAsset{Code: "USDC", Issuer: keypair.MustRandom().Address()}
+
+	sac, deployed, err := deployer.DeployStellarAssetContract(context.Background(), usdc)
+	require.NoError(t, err)
+	require.True(t, deployed)
+	require.Len(t, *submitted, 1)
+	want, err := types.NewStellarAssetFromClassic(usdc, client.StandaloneNetwork().Passphrase)
+	require.NoError(t, err)
+	require.Equal(t, want.Asset.ContractID(), sac.Asset.ContractID())
+
+	addr, err := sac.MakeScAddress()
+	require.NoError(t, err)
+	key, err := xdr.MarshalBase64(client.ContractInstanceLedgerKey(addr))
+	require.NoError(t, err)
+	entries[key] = ""
+	_, deployed, err = deployer.DeployStellarAssetContract(context.Background(), usdc)
+	require.NoError(t, err)
+	require.False(t, deployed)
+	require.Len(t, *submitted, 1)
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/stellar/go/txnbuild"
+)
+
+// ErrBadSequence is returned when a transaction was rejected because its
+// sequence number did not match the source account (txBAD_SEQ).
+var ErrBadSequence = errors.New("bad sequence number (txBAD_SEQ)")
+
+// AccountLoadFunc loads the current state of a source account from the ledger.
+type AccountLoadFunc func(ctx context.Context) (txnbuild.Account, error)
+
+// SequenceManager tracks the sequence number of a source account locally. It
+// hands out consecutive sequence numbers to concurrently built transactions,
+// so the account does not have to be reloaded for every transaction.
+type SequenceManager struct {
+	mu      sync.Mutex
+	load    AccountLoadFunc
+	address string
+	seq     int64
+	synced  bool
+}
+
+// NewSequenceManager creates a SequenceManager that syncs with the ledger
+// using the given load function.
+func NewSequenceManager(load AccountLoadFunc) *SequenceManager {
+	return &SequenceManager{load: load}
+}
+
+// Account returns the source account at the last reserved sequence number
+// without reserving a new one. It is meant for simulations.
+func (m *SequenceManager) Account(ctx context.Context) (*txnbuild.SimpleAccount, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.sync(ctx); err != nil {
+		return nil, err
+	}
+	return &txnbuild.SimpleAccount{AccountID: m.address, Sequence: m.seq}, nil
+}
+
+// Next reserves the next sequence number. The returned account is meant to
+// be used with IncrementSequenceNum, so that the built transaction carries
+// the reserved number.
+func (m *SequenceManager) Next(ctx context.Context) (*txnbuild.SimpleAccount, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.sync(ctx); err != nil {
+		return nil, err
+	}
+	acc := &txnbuild.SimpleAccount{AccountID: m.address, Sequence: m.seq}
+	m.seq++
+	return acc, nil
+}
+
+// Reset discards the locally tracked sequence number. The next call to
+// Account or Next resyncs with the ledger. It is meant to be called after a
+// transaction was rejected with txBAD_SEQ, since the numbers reserved by
+// other pending transactions are handed out again after a resync.
+func (m *SequenceManager) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.synced = false
+}
+
+// Release hands back the sequence number of a transaction that was rejected
+// without being applied, i.e., that did not consume its number. If no number
+// was reserved after it, it is handed out again by the next call to Next.
+// Otherwise, the numbers reserved after it can no longer be applied and the
+// sequence number is resynced.
+func (m *SequenceManager) Release(seq int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.synced {
+		return
+	}
+	if seq == m.seq {
+		m.seq--
+		return
+	}
+	m.synced = false
+}
+
+// sync loads the account from the ledger if the sequence number is not
+// tracked yet. The caller must hold the lock.
+func (m *SequenceManager) sync(ctx context.Context) error {
+	if m.synced {
+		return nil
+	}
+	acc, err := m.load(ctx)
+	if err != nil {
+		return err
+	}
+	seq, err := acc.GetSequenceNumber()
+	if err != nil {
+		return err
+	}
+	m.address = acc.GetAccountID()
+	m.seq = seq
+	m.synced = true
+	return nil
+}
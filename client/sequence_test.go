@@ -0,0 +1,151 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/perun-stellar-backend/client"
+)
+
+func TestSequenceManagerConcurrent(t *testing.T) {
+	loads := 0
+	seqMgr := client.NewSequenceManager(func(context.Context) (txnbuild.Account, error) {
+		loads++
+		return &txnbuild.SimpleAccount{AccountID: "GABC", Sequence: 100}, nil
+	})
+
+	const n = 50
+	var mu sync.Mutex
+	seen := make(map[int64]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			acc, err := seqMgr.Next(context.Background())
+			require.NoError(t, err)
+			mu.Lock()
+			defer mu.Unlock()
+			seen[acc.Sequence] = true
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, seen, n)
+	for seq := int64(100); seq < 100+n; seq++ {
+		require.True(t, seen[seq], "sequence %d not handed out", seq)
+	}
+	require.Equal(t, 1, loads)
+
+	acc, err := seqMgr.Account(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(100+n), acc.Sequence)
+
+	seqMgr.Reset()
+	acc, err = seqMgr.Next(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(100), acc.Sequence)
+	require.Equal(t, 2, loads)
+}
+
+func TestSequenceManagerRelease(t *testing.T) {
+	loads := 0
+	seqMgr := client.NewSequenceManager(func(context.Context) (txnbuild.Account, error) {
+		loads++
+		return &txnbuild.SimpleAccount{AccountID: "GABC", Sequence: 100}, nil
+	})
+	ctx := context.Background()
+
+	// The last reserved number is handed out again.
+	_, err := seqMgr.Next(ctx)
+	require.NoError(t, err)
+	seqMgr.Release(101)
+	acc, err := seqMgr.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), acc.Sequence)
+	require.Equal(t, 1, loads)
+
+	// Numbers reserved after a released one cannot be applied anymore.
+	_, err = seqMgr.Next(ctx)
+	require.NoError(t, err)
+	seqMgr.Release(101)
+	_, err = seqMgr.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, loads)
+}
+
+func TestInvokeSignedTxResyncsOnBadSeq(t *testing.T) {
+	kp := keypair.MustRandom()
+	rpc, url := newFakeRPC(t)
+	network := client.StandaloneNetwork()
+	network.SorobanRPCURL = url
+
+	rpc.handle("getLedgerEntries", func(json.RawMessage) interface{} {
+		return client.RPCGetLedgerEntriesResponse{
+			Entries: []client.RPCLedgerEntry{{XDR: accountEntryXdr(t, kp, 41)}},
+		}
+	})
+	txData, err := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+	require.NoError(t, err)
+	retXdr, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	require.NoError(t, err)
+	rpc.handle("simulateTransaction", func(json.RawMessage) interface{} {
+		return client.RPCSimulateTxResponse{
+			TransactionData: txData,
+			Results:         []client.RPCSimulateHostFunctionResult{{XDR: retXdr}},
+		}
+	})
+	badSeq, err := xdr.MarshalBase64(xdr.TransactionResult{
+		Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxBadSeq},
+	})
+	require.NoError(t, err)
+	rpc.handle("sendTransaction", func(json.RawMessage) interface{} {
+		if rpc.count("sendTransaction") == 1 {
+			return client.RPCSendTxResponse{Status: client.TxStatusError, ErrorResultXdr: badSeq}
+		}
+		return client.RPCSendTxResponse{Status: client.TxStatusPending, Hash: "abc"}
+	})
+	meta, err := xdr.MarshalBase64(xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{}})
+	require.NoError(t, err)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+
+	sender := client.NewRPCSender(kp, network)
+	sender.SetPolling(time.Millisecond, 5)
+	cfg := client.TransactorConfig{}
+	cfg.SetKeyPair(kp)
+	cfg.SetNetwork(network)
+	cfg.SetSender(sender)
+	cfg.SetRetryPolicy(fastRetryPolicy())
+	cb := client.NewContractBackend(&cfg)
+
+	contract := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.Hash{1}}
+	_, err = cb.InvokeSignedTx(context.Background(), "open", xdr.ScVec{}, contract)
+	require.NoError(t, err)
+	require.Equal(t, 2, rpc.count("sendTransaction"))
+	// Initial sync and resync after txBAD_SEQ.
+	require.Equal(t, 2, rpc.count("getLedgerEntries"))
+}
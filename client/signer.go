@@ -0,0 +1,124 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// TxSigner signs on behalf of a Stellar account. Implementations may keep
+// the secret key out of process, e.g. in a HSM or key management service.
+type TxSigner interface {
+	// Address returns the public address of the account.
+	Address() string
+	// SignTxHash signs the hash of a transaction and returns the ed25519
+	// signature.
+	SignTxHash(ctx context.Context, hash [32]byte) ([]byte, error)
+	// SignAuthEntry signs the preimage of a Soroban authorization entry and
+	// returns the ed25519 signature of its hash.
+	SignAuthEntry(ctx context.Context, preimage xdr.HashIdPreimage) ([]byte, error)
+}
+
+// KeypairSigner is a TxSigner holding the secret key in memory.
+type KeypairSigner struct {
+	kp *keypair.Full
+}
+
+var _ TxSigner = (*KeypairSigner)(nil)
+
+// NewKeypairSigner creates a new KeypairSigner.
+func NewKeypairSigner(kp *keypair.Full) *KeypairSigner {
+	return &KeypairSigner{kp: kp}
+}
+
+// Address returns the address of the keypair.
+func (s *KeypairSigner) Address() string {
+	return s.kp.Address()
+}
+
+// SignTxHash signs the transaction hash with the keypair.
+func (s *KeypairSigner) SignTxHash(_ context.Context, hash [32]byte) ([]byte, error) {
+	return s.kp.Sign(hash[:])
+}
+
+// SignAuthEntry signs the hash of the authorization preimage with the keypair.
+func (s *KeypairSigner) SignAuthEntry(_ context.Context, preimage xdr.HashIdPreimage) ([]byte, error) {
+	payload, err := preimage.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(payload)
+	return s.kp.Sign(hash[:])
+}
+
+// txSignFunc adds signatures to a transaction.
+type txSignFunc func(context.Context, *txnbuild.Transaction) (*txnbuild.Transaction, error)
+
+// signWith returns a txSignFunc signing with the given signer, or nil if the
+// signer is nil.
+func (c *ContractBackend) signWith(signer TxSigner) txSignFunc {
+	if signer == nil {
+		return nil
+	}
+	return func(ctx context.Context, tx *txnbuild.Transaction) (*txnbuild.Transaction, error) {
+		return signTx(ctx, signer, c.tr.network.Passphrase, tx)
+	}
+}
+
+// decoratedSignature signs the hash and attaches the signature hint of the
+// signer's address.
+func decoratedSignature(ctx context.Context, signer TxSigner, hash [32]byte) (xdr.DecoratedSignature, error) {
+	sig, err := signer.SignTxHash(ctx, hash)
+	if err != nil {
+		return xdr.DecoratedSignature{}, err
+	}
+	kp, err := keypair.ParseAddress(signer.Address())
+	if err != nil {
+		return xdr.DecoratedSignature{}, err
+	}
+	return xdr.DecoratedSignature{Hint: kp.Hint(), Signature: sig}, nil
+}
+
+// signTx signs the transaction with the given signer.
+func signTx(ctx context.Context, signer TxSigner, passphrase string, tx *txnbuild.Transaction) (*txnbuild.Transaction, error) {
+	hash, err := tx.Hash(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := decoratedSignature(ctx, signer, hash)
+	if err != nil {
+		return nil, err
+	}
+	return tx.AddSignatureDecorated(sig)
+}
+
+// signFeeBumpTx signs the fee-bump transaction with the given signer.
+func signFeeBumpTx(ctx context.Context, signer TxSigner, passphrase string, tx *txnbuild.FeeBumpTransaction,
+) (*txnbuild.FeeBumpTransaction, error) {
+	hash, err := tx.Hash(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := decoratedSignature(ctx, signer, hash)
+	if err != nil {
+		return nil, err
+	}
+	return tx.AddSignatureDecorated(sig)
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/perun-stellar-backend/client"
+	ctest "perun.network/perun-stellar-backend/client/test"
+)
+
+func newRemoteSigner(t *testing.T, signer client.TxSigner) *client.RemoteSigner {
+	srv := httptest.NewServer(ctest.NewSignerServer(signer))
+	t.Cleanup(srv.Close)
+	remote, err := client.NewRemoteSigner(context.Background(), srv.URL, nil)
+	require.NoError(t, err)
+	return remote
+}
+
+func TestRemoteSignerSignsTransactions(t *testing.T) {
+	kp := keypair.MustRandom()
+	remote := newRemoteSigner(t, client.NewKeypairSigner(kp))
+	require.Equal(t, kp.Address(), remote.Address())
+
+	rpc, url := newFakeRPC(t)
+	net := client.StandaloneNetwork()
+	net.SorobanRPCURL = url
+	rpc.handle("getLedgerEntries", func(json.RawMessage) interface{} {
+		return client.RPCGetLedgerEntriesResponse{
+			Entries: []client.RPCLedgerEntry{{XDR: accountEntryXdr(t, kp, 41)}},
+		}
+	})
+	txData, err := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+	require.NoError(t, err)
+	retXdr, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	require.NoError(t, err)
+	rpc.handle("simulateTransaction", func(json.RawMessage) interface{} {
+		return client.RPCSimulateTxResponse{
+			TransactionData: txData,
+			Results:         []client.RPCSimulateHostFunctionResult{{XDR: retXdr}},
+		}
+	})
+	var submitted *txnbuild.Transaction
+	rpc.handle("sendTransaction", func(params json.RawMessage) interface{} {
+		var req struct {
+			Transaction string `json:"transaction"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		generic, err := txnbuild.TransactionFromXDR(req.Transaction)
+		require.NoError(t, err)
+		var ok bool
+		submitted, ok = generic.Transaction()
+		require.True(t, ok)
+		return client.RPCSendTxResponse{Status: client.TxStatusPending}
+	})
+	meta := successMeta(t)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+
+	sender := client.NewRPCSender(nil, net)
+	sender.SetPolling(time.Millisecond, 5)
+	cfg := client.TransactorConfig{}
+	cfg.SetSigner(remote)
+	cfg.SetNetwork(net)
+	cfg.SetSender(sender)
+	cb := client.NewContractBackend(&cfg)
+
+	addr, err := cb.GetTransactor().GetAddress()
+	require.NoError(t, err)
+	require.Equal(t, kp.Address(), addr)
+
+	_, err = cb.InvokeSignedTx(context.Background(), "close", xdr.ScVec{}, testContract)
+	require.NoError(t, err)
+	require.NotNil(t, submitted)
+	hash, err := submitted.Hash(net.Passphrase)
+	require.NoError(t, err)
+	sigs := submitted.Signatures()
+	require.Len(t, sigs, 1)
+	require.Equal(t, kp.Hint(), [4]byte(sigs[0].Hint))
+	require.NoError(t, kp.Verify(hash[:], sigs[0].Signature))
+}
+
+func TestRemoteSignerSignsAuthEntries(t *testing.T) {
+	kp := keypair.MustRandom()
+	remote := newRemoteSigner(t, client.NewKeypairSigner(kp))
+
+	signed, err := client.SignAuthEntries(context.Background(),
+		[]xdr.SorobanAuthorizationEntry{addressAuthEntry(t, kp, testContract)},
+		remote, network.TestNetworkPassphrase, 1000)
+	require.NoError(t, err)
+	verifyAuthEntry(t, signed[0], kp, network.TestNetworkPassphrase)
+}
+
+// refusingSigner is a TxSigner whose signing service refuses every request.
+type refusingSigner struct {
+	address string
+}
+
+func (s refusingSigner) Address() string { return s.address }
+
+func (refusingSigner) SignTxHash(context.Context, [32]byte) ([]byte, error) {
+	return nil, errors.New("policy violation")
+}
+
+func (refusingSigner) SignAuthEntry(context.Context, xdr.HashIdPreimage) ([]byte, error) {
+	return nil, errors.New("policy violation")
+}
+
+func TestRemoteSignerRefused(t *testing.T) {
+	remote := newRemoteSigner(t, refusingSigner{address: keypair.MustRandom().Address()})
+
+	_, err := remote.SignTxHash(context.Background(), [32]byte{1})
+	require.ErrorIs(t, err, client.ErrRemoteSigner)
+	require.ErrorContains(t, err, "policy violation")
+}
+
+// forgingSigner is a TxSigner whose signing service signs for another key
+// than the address it claims.
+type forgingSigner struct {
+	address string
+	*client.KeypairSigner
+}
+
+func (s forgingSigner) Address() string { return s.address }
+
+func TestRemoteSignerVerifiesSignatures(t *testing.T) {
+	remote := newRemoteSigner(t, forgingSigner{
+		address:       keypair.MustRandom().Address(),
+		KeypairSigner: client.NewKeypairSigner(keypair.MustRandom()),
+	})
+
+	_, err := remote.SignTxHash(context.Background(), [32]byte{1})
+	require.ErrorIs(t, err, client.ErrRemoteSigner)
+	kp := keypair.MustParseAddress(remote.Address())
+	_, err = client.SignAuthEntries(context.Background(),
+		[]xdr.SorobanAuthorizationEntry{addressAuthEntry(t, kp, testContract)},
+		remote, network.TestNetworkPassphrase, 1000)
+	require.ErrorIs(t, err, client.ErrRemoteSigner)
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// ErrNoFeeBumpSender is returned when a fee sponsor is used with a sender
+// that cannot submit fee-bump transactions.
+var ErrNoFeeBumpSender = errors.New("sender does not support fee-bump transactions")
+
+// FeeSponsor pays the fees of transactions on behalf of a participant. It
+// wraps the signed inner transaction into a signed fee-bump transaction.
+type FeeSponsor interface {
+	SponsorTx(ctx context.Context, inner *txnbuild.Transaction) (*txnbuild.FeeBumpTransaction, error)
+}
+
+// FeeBumpSender is implemented by senders that can submit fee-bump transactions.
+type FeeBumpSender interface {
+	SendFeeBumpTx(context.Context, *txnbuild.FeeBumpTransaction) (xdr.TransactionMeta, error)
+}
+
+var _ FeeBumpSender = (*TxSender)(nil)
+
+// LocalFeeSponsor is a FeeSponsor that signs fee-bump transactions with a
+// TxSigner of the fee account. It stands in for a remote relayer, e.g. in tests.
+type LocalFeeSponsor struct {
+	signer  TxSigner
+	network NetworkConfig
+}
+
+var _ FeeSponsor = (*LocalFeeSponsor)(nil)
+
+// NewLocalFeeSponsor creates a new LocalFeeSponsor paying fees from the
+// account of the given keypair.
+func NewLocalFeeSponsor(kp *keypair.Full, network NetworkConfig) *LocalFeeSponsor {
+	return NewLocalFeeSponsorWithSigner(NewKeypairSigner(kp), network)
+}
+
+// NewLocalFeeSponsorWithSigner creates a new LocalFeeSponsor paying fees from
+// the account of the given signer.
+func NewLocalFeeSponsorWithSigner(signer TxSigner, network NetworkConfig) *LocalFeeSponsor {
+	return &LocalFeeSponsor{signer: signer, network: network}
+}
+
+// Address returns the address of the account paying the fees.
+func (s *LocalFeeSponsor) Address() string {
+	return s.signer.Address()
+}
+
+// SponsorTx wraps the inner transaction into a fee-bump transaction paid by
+// the sponsor. The fee-bump offers the same inclusion fee as the inner
+// transaction, see NewFeeBumpTx.
+func (s *LocalFeeSponsor) SponsorTx(ctx context.Context, inner *txnbuild.Transaction) (*txnbuild.FeeBumpTransaction, error) {
+	feeBump, err := NewFeeBumpTx(inner, s.signer.Address())
+	if err != nil {
+		return nil, err
+	}
+	return signFeeBumpTx(ctx, s.signer, s.network.Passphrase, feeBump)
+}
+
+// NewFeeBumpTx wraps the inner transaction into an unsigned fee-bump
+// transaction paid by feeAccount, which offers the inclusion fee of the inner
+// transaction per operation. The fee of a fee-bump counts one operation more
+// than its inner transaction. The Soroban resource fee of the inner
+// transaction is charged only once, so it is added to the fee of the bump
+// instead of being multiplied with the operations like the inclusion fee.
+func NewFeeBumpTx(inner *txnbuild.Transaction, feeAccount string) (*txnbuild.FeeBumpTransaction, error) {
+	feeBump, err := txnbuild.NewFeeBumpTransaction(txnbuild.FeeBumpTransactionParams{
+		Inner:      inner,
+		FeeAccount: feeAccount,
+		BaseFee:    inner.BaseFee(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resourceFee := sorobanResourceFee(inner)
+	if resourceFee == 0 {
+		return feeBump, nil
+	}
+
+	// txnbuild takes the base fee of the inner transaction, which includes
+	// the resource fee, for every operation of the fee-bump.
+	numOps := int64(len(inner.Operations()))
+	inclusionFee := max((inner.MaxFee()-resourceFee)/numOps, txnbuild.MinBaseFee)
+	env := feeBump.ToXDR()
+	bump := *env.FeeBump
+	bump.Tx.Fee = xdr.Int64(resourceFee + inclusionFee*(numOps+1))
+	env.FeeBump = &bump
+	envXdr, err := xdr.MarshalBase64(env)
+	if err != nil {
+		return nil, err
+	}
+	generic, err := txnbuild.TransactionFromXDR(envXdr)
+	if err != nil {
+		return nil, err
+	}
+	feeBump, ok := generic.FeeBump()
+	if !ok {
+		return nil, errors.New("expected a fee-bump transaction")
+	}
+	return feeBump, nil
+}
+
+// sorobanResourceFee returns the resource fee declared in the Soroban data
+// of the transaction, or zero if it is no Soroban transaction.
+func sorobanResourceFee(tx *txnbuild.Transaction) int64 {
+	env := tx.ToXDR()
+	if env.V1 == nil || env.V1.Tx.Ext.SorobanData == nil {
+		return 0
+	}
+	return int64(env.V1.Tx.Ext.SorobanData.ResourceFee)
+}
+
+// sponsoredSend returns a signSendFunc that signs the inner transaction with
+// sign and submits it wrapped into a fee-bump transaction of the sponsor.
+func (c *ContractBackend) sponsoredSend(sign txSignFunc) (signSendFunc, error) {
+	sender, ok := c.tr.sender.(FeeBumpSender)
+	if !ok {
+		return nil, ErrNoFeeBumpSender
+	}
+	if sign == nil {
+		return nil, errors.New("fee sponsorship requires the signer of the transaction source")
+	}
+	return func(ctx context.Context, txUnsigned txnbuild.Transaction) (string, xdr.TransactionMeta, error) {
+		inner, err := sign(ctx, &txUnsigned)
+		if err != nil {
+			return "", xdr.TransactionMeta{}, err
+		}
+		feeBump, err := c.tr.sponsor.SponsorTx(ctx, inner)
+		if err != nil {
+			return "", xdr.TransactionMeta{}, errors.Join(errors.New("fee sponsor rejected transaction"), err)
+		}
+		hash, err := feeBump.HashHex(c.tr.network.Passphrase)
+		if err != nil {
+			return "", xdr.TransactionMeta{}, err
+		}
+		txMeta, err := sender.SendFeeBumpTx(ctx, feeBump)
+		return hash, txMeta, err
+	}, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/perun-stellar-backend/client"
+)
+
+func TestFeeSponsor(t *testing.T) {
+	participant := keypair.MustRandom()
+	sponsorKp := keypair.MustRandom()
+	rpc, url := newFakeRPC(t)
+	network := client.StandaloneNetwork()
+	network.SorobanRPCURL = url
+
+	rpc.handle("getLedgerEntries", func(json.RawMessage) interface{} {
+		return client.RPCGetLedgerEntriesResponse{
+			Entries: []client.RPCLedgerEntry{{XDR: accountEntryXdr(t, participant, 41)}},
+		}
+	})
+	txData, err := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+	require.NoError(t, err)
+	retXdr, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	require.NoError(t, err)
+	rpc.handle("simulateTransaction", func(json.RawMessage) interface{} {
+		return client.RPCSimulateTxResponse{
+			TransactionData: txData,
+			Results:         []client.RPCSimulateHostFunctionResult{{XDR: retXdr}},
+			MinResourceFee:  1000,
+		}
+	})
+	var feeBump *txnbuild.FeeBumpTransaction
+	rpc.handle("sendTransaction", func(params json.RawMessage) interface{} {
+		var req struct {
+			Transaction string `json:"transaction"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		generic, err := txnbuild.TransactionFromXDR(req.Transaction)
+		require.NoError(t, err)
+		var ok bool
+		feeBump, ok = generic.FeeBump()
+		require.True(t, ok, "expected a fee-bump transaction")
+		return client.RPCSendTxResponse{Status: client.TxStatusPending, Hash: "abc"}
+	})
+	meta, err := xdr.MarshalBase64(xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{}})
+	require.NoError(t, err)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+
+	sender := client.NewRPCSender(participant, network)
+	sender.SetPolling(time.Millisecond, 5)
+	cfg := client.TransactorConfig{}
+	cfg.SetKeyPair(participant)
+	cfg.SetNetwork(network)
+	cfg.SetSender(sender)
+	cfg.SetFeeSponsor(client.NewLocalFeeSponsor(sponsorKp, network))
+	cb := client.NewContractBackend(&cfg)
+
+	contract := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.Hash{1}}
+	_, err = cb.InvokeSignedTx(context.Background(), "fund", xdr.ScVec{}, contract)
+	require.NoError(t, err)
+
+	require.NotNil(t, feeBump)
+	require.Equal(t, sponsorKp.Address(), feeBump.FeeAccount())
+	// The resource fee of 1000 is paid once, the inclusion fee for the inner
+	// operation and the fee-bump.
+	require.Equal(t, int64(1000+2*client.DefaultInclusionFee), feeBump.MaxFee())
+	require.Equal(t, int64(1000+client.DefaultInclusionFee), feeBump.InnerTransaction().MaxFee())
+	inner := feeBump.InnerTransaction()
+	require.Equal(t, participant.Address(), inner.SourceAccount().AccountID)
+	require.Equal(t, int64(42), inner.SequenceNumber())
+
+	innerHash, err := inner.Hash(network.Passphrase)
+	require.NoError(t, err)
+	require.Len(t, inner.Signatures(), 1)
+	require.NoError(t, participant.Verify(innerHash[:], inner.Signatures()[0].Signature))
+	outerHash, err := feeBump.Hash(network.Passphrase)
+	require.NoError(t, err)
+	require.Len(t, feeBump.Signatures(), 1)
+	require.NoError(t, sponsorKp.Verify(outerHash[:], feeBump.Signatures()[0].Signature))
+}
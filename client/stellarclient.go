@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/stellar/go/xdr"
 	pchannel "perun.network/go-perun/channel"
@@ -10,12 +11,23 @@ import (
 	"perun.network/perun-stellar-backend/wire"
 )
 
+// Invoker invokes the functions of the Perun contract on behalf of a
+// participant. It is implemented by ContractBackend and can be replaced, e.g.
+// by a simulated contract in tests.
 type Invoker interface {
 	Open(ctx context.Context, perunAddr xdr.ScAddress, params *pchannel.Params, state *pchannel.State) error
 	Abort(ctx context.Context, perunAddr xdr.ScAddress, state *pchannel.State) error
-	Fund(ctx context.Context, perunAddr xdr.ScAddress, assetAddr xdr.ScAddress, chanID pchannel.ID, funderIdx bool) error
+	Fund(ctx context.Context, perunAddr xdr.ScAddress, chanID pchannel.ID, funderIdx bool) error
 	Dispute(ctx context.Context, perunAddr xdr.ScAddress, state *pchannel.State, sigs []pwallet.Sig) error
 	Close(ctx context.Context, perunAddr xdr.ScAddress, state *pchannel.State, sigs []pwallet.Sig) error
 	ForceClose(ctx context.Context, perunAddr xdr.ScAddress, chanID pchannel.ID) error
+	Withdraw(ctx context.Context, perunAddr xdr.ScAddress, req pchannel.AdjudicatorReq, withdrawerIdx bool, oneWithdrawer bool) error
 	GetChannelInfo(ctx context.Context, perunAddr xdr.ScAddress, chanID pchannel.ID) (wire.Channel, error)
+	// TokenBalance returns the balance of the participant in the given token.
+	TokenBalance(ctx context.Context, token xdr.ScAddress) (*big.Int, error)
+	// CheckTrustlines checks that the participant's account can hold the
+	// given assets.
+	CheckTrustlines(ctx context.Context, assets []pchannel.Asset) error
 }
+
+var _ Invoker = (*ContractBackend)(nil)
@@ -0,0 +1,324 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+var (
+	// ErrTryAgainLater is returned when the network did not accept the
+	// transaction for now, e.g. because of congestion.
+	ErrTryAgainLater = errors.New("transaction not accepted, try again later")
+	// ErrInsufficientFee is returned when the fee of a transaction was too
+	// low to be included (txINSUFFICIENT_FEE).
+	ErrInsufficientFee = errors.New("insufficient fee (txINSUFFICIENT_FEE)")
+	// ErrTxTooLate is returned when a transaction expired before it was
+	// included in a ledger (txTOO_LATE).
+	ErrTxTooLate = errors.New("transaction expired (txTOO_LATE)")
+	// ErrTxRejected is returned when a transaction was rejected without
+	// being applied for another reason, e.g. txBAD_AUTH.
+	ErrTxRejected = errors.New("transaction rejected")
+)
+
+// RetryPolicy configures the resubmission of transactions.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of submissions of a transaction.
+	MaxAttempts int
+	// InitialBackoff is the wait time before the first resubmission. It is
+	// doubled for every further resubmission, up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// TxValidity is the time span in which a transaction can be included in a
+	// ledger. Afterwards, it is rebuilt with a new fee.
+	TxValidity time.Duration
+}
+
+// DefaultRetryPolicy returns the RetryPolicy used if none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,               //nolint:gomnd
+		InitialBackoff: time.Second,     //nolint:gomnd
+		MaxBackoff:     8 * time.Second, //nolint:gomnd
+		TxValidity:     2 * time.Minute, //nolint:gomnd
+	}
+}
+
+// TxError is returned when a transaction could not be executed
+// successfully. It carries the final status of the last submitted
+// transaction, e.g. TxStatusFailed or TxStatusTryAgainLater.
+type TxError struct {
+	Hash     string
+	Status   string
+	Attempts int
+	Err      error
+}
+
+func (e *TxError) Error() string {
+	return fmt.Sprintf("transaction %s: status %s after %d attempt(s): %v", e.Hash, e.Status, e.Attempts, e.Err)
+}
+
+func (e *TxError) Unwrap() error {
+	return e.Err
+}
+
+// txStatusOf returns the transaction status corresponding to a submission error.
+func txStatusOf(err error) string {
+	switch {
+	case errors.Is(err, ErrTxFailed):
+		return TxStatusFailed
+	case errors.Is(err, ErrTryAgainLater):
+		return TxStatusTryAgainLater
+	case errors.Is(err, ErrTxNotConfirmed):
+		return TxStatusNotFound
+	default:
+		return TxStatusError
+	}
+}
+
+// signSendFunc signs and sends a transaction. It returns the hash of the
+// submitted envelope, which differs from the hash of the given transaction
+// if it is wrapped into a fee-bump.
+type signSendFunc func(context.Context, txnbuild.Transaction) (string, xdr.TransactionMeta, error)
+
+// senderSend returns a signSendFunc using the Sender of the transactor.
+func (c *ContractBackend) senderSend() signSendFunc {
+	return func(ctx context.Context, txUnsigned txnbuild.Transaction) (string, xdr.TransactionMeta, error) {
+		hash, err := txUnsigned.HashHex(c.tr.network.Passphrase)
+		if err != nil {
+			return "", xdr.TransactionMeta{}, err
+		}
+		txMeta, err := c.tr.sender.SignSendTx(ctx, txUnsigned)
+		return hash, txMeta, err
+	}
+}
+
+// defaultSend returns the signSendFunc for transactions sourced from the
+// participant's account. If a fee sponsor is set, they are wrapped into a
+// fee-bump transaction.
+func (c *ContractBackend) defaultSend() (signSendFunc, error) {
+	if c.tr.isMultisig() {
+		return c.multisigSend()
+	}
+	if c.tr.sponsor != nil {
+		return c.sponsoredSend(c.signWith(c.tr.signer))
+	}
+	return c.senderSend(), nil
+}
+
+// submit builds a transaction with the next sequence number and the fee
+// chosen by the fee strategy, sends it and resubmits it until it is executed
+// or the retry policy is exhausted:
+//   - on TRY_AGAIN_LATER or if it was not confirmed in time, the identical
+//     transaction is resubmitted, which is idempotent by its hash.
+//   - on txBAD_SEQ, the sequence number is resynced and the transaction rebuilt.
+//   - on txINSUFFICIENT_FEE or txTOO_LATE, it is rebuilt with the same
+//     sequence number and an escalated fee.
+//
+// The sequence number is only resynced on txBAD_SEQ. Otherwise, the numbers
+// reserved by concurrent transactions would be handed out twice. If the
+// transaction is rejected for another reason, its number is handed back.
+//
+// Before a transaction is rebuilt, all previously submitted hashes whose
+// outcome is unknown are looked up, so that a transaction that made it into a
+// ledger is never executed twice.
+func (c *ContractBackend) submit(ctx context.Context, seq *SequenceManager, send signSendFunc,
+	feeReq FeeRequest, op txnbuild.Operation,
+) (xdr.TransactionMeta, error) {
+	policy := c.tr.retry
+	backoff := policy.InitialBackoff
+	var (
+		tx      *txnbuild.Transaction
+		source  *txnbuild.SimpleAccount
+		pending []string
+		hash    string
+		err     error
+	)
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return xdr.TransactionMeta{}, &TxError{Hash: hash, Status: txStatusOf(err), Attempts: attempt - 1, Err: ctx.Err()}
+			case <-time.After(backoff):
+			}
+			backoff = min(2*backoff, policy.MaxBackoff) //nolint:gomnd
+		}
+
+		if tx == nil {
+			if len(pending) > 0 {
+				if txMeta, done, err := c.lookupSubmitted(ctx, pending, attempt-1); done {
+					return txMeta, err
+				}
+			}
+			tx, err = c.buildTx(ctx, seq, source, feeReq, op)
+			if err != nil {
+				return xdr.TransactionMeta{}, err
+			}
+		}
+
+		var txMeta xdr.TransactionMeta
+		hash, txMeta, err = send(ctx, *tx)
+		if errors.Is(err, ErrTxNotConfirmed) && (len(pending) == 0 || pending[len(pending)-1] != hash) {
+			pending = append(pending, hash)
+		}
+		if err == nil {
+			return txMeta, nil
+		}
+
+		switch {
+		case errors.Is(err, ErrTxFailed):
+			// The transaction was executed and consumed its sequence number.
+			return xdr.TransactionMeta{}, &TxError{Hash: hash, Status: TxStatusFailed, Attempts: attempt, Err: err}
+		case errors.Is(err, ErrTryAgainLater), errors.Is(err, ErrTxNotConfirmed):
+		case errors.Is(err, ErrBadSequence):
+			seq.Reset()
+			source, tx = nil, nil
+		case errors.Is(err, ErrInsufficientFee), errors.Is(err, ErrTxTooLate):
+			// The transaction did not consume its sequence number, so it
+			// is rebuilt with the same one.
+			source = &txnbuild.SimpleAccount{AccountID: tx.SourceAccount().AccountID, Sequence: tx.SequenceNumber() - 1}
+			feeReq.Attempt++
+			tx = nil
+		case errors.Is(err, ErrTxRejected) && len(pending) == 0:
+			// The transaction was not applied, so its sequence number is
+			// handed back for the next transaction.
+			seq.Release(tx.SequenceNumber())
+			return xdr.TransactionMeta{}, &TxError{Hash: hash, Status: txStatusOf(err), Attempts: attempt, Err: err}
+		default:
+			return xdr.TransactionMeta{}, &TxError{Hash: hash, Status: txStatusOf(err), Attempts: attempt, Err: err}
+		}
+	}
+	return xdr.TransactionMeta{}, &TxError{Hash: hash, Status: txStatusOf(err), Attempts: policy.MaxAttempts, Err: err}
+}
+
+// buildTx builds the transaction of the invocation from the source account,
+// or with the next sequence number if source is nil. Its validity is limited
+// by the retry policy, so that a transaction that is stuck can be replaced
+// after it expired.
+func (c *ContractBackend) buildTx(ctx context.Context, seq *SequenceManager, source *txnbuild.SimpleAccount,
+	feeReq FeeRequest, op txnbuild.Operation,
+) (*txnbuild.Transaction, error) {
+	feeOp, fee, err := c.applyFee(ctx, feeReq, op)
+	if err != nil {
+		return nil, err
+	}
+	acc := source
+	if acc == nil {
+		acc, err = seq.Next(ctx)
+		if err != nil {
+			return nil, errors.Join(errors.New("failed to load source account"), err)
+		}
+	}
+	txParams := GetBaseTransactionParamsWithFee(acc, fee, feeOp)
+	if c.tr.retry.TxValidity > 0 {
+		txParams.Preconditions.TimeBounds = txnbuild.NewTimeout(int64(c.tr.retry.TxValidity.Seconds()))
+	}
+	tx, err := txnbuild.NewTransaction(txParams)
+	if err != nil {
+		return nil, errors.Join(errors.New("error building Transaction"), err)
+	}
+	return tx, nil
+}
+
+// lookupSubmitted queries the status of previously submitted transactions.
+// It reports done if one of them was executed, together with its result.
+func (c *ContractBackend) lookupSubmitted(ctx context.Context, hashes []string, attempts int) (xdr.TransactionMeta, bool, error) {
+	rpc := c.tr.network.NewRPCClient()
+	defer rpc.Close()
+	for _, hash := range hashes {
+		result, err := getTransaction(ctx, rpc, hash)
+		if err != nil || result.Status == TxStatusNotFound {
+			continue
+		}
+		txMeta, err := decodeTxResult(result)
+		if err != nil {
+			return xdr.TransactionMeta{}, true, &TxError{Hash: hash, Status: result.Status, Attempts: attempts, Err: err}
+		}
+		return txMeta, true, nil
+	}
+	return xdr.TransactionMeta{}, false, nil
+}
+
+// classifyResultCode maps the result code of a rejected transaction to an error.
+func classifyResultCode(code xdr.TransactionResultCode) error {
+	switch code {
+	case xdr.TransactionResultCodeTxBadSeq:
+		return ErrBadSequence
+	case xdr.TransactionResultCodeTxInsufficientFee:
+		return ErrInsufficientFee
+	case xdr.TransactionResultCodeTxTooLate:
+		return ErrTxTooLate
+	default:
+		return fmt.Errorf("%w: %s", ErrTxRejected, code)
+	}
+}
+
+// classifyResultXdr classifies the base64 encoded result of a rejected
+// transaction. For fee-bump transactions, the inner result is used if the
+// outer one does not carry the reason.
+func classifyResultXdr(resultXdr string) error {
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(resultXdr, &result); err != nil {
+		return fmt.Errorf("%w: %s", ErrTxRejected, resultXdr)
+	}
+	if inner, ok := result.Result.GetInnerResultPair(); ok {
+		return classifyResultCode(inner.Result.Result.Code)
+	}
+	return classifyResultCode(result.Result.Code)
+}
+
+// classifyHorizonError maps an error returned by a Horizon submission to the
+// errors the submission engine reacts on. The original error is kept.
+func classifyHorizonError(err error) error {
+	hErr := horizonclient.GetError(err)
+	if hErr == nil {
+		return err
+	}
+	switch hErr.Problem.Status {
+	case http.StatusGatewayTimeout:
+		return errors.Join(ErrTxNotConfirmed, err)
+	case http.StatusServiceUnavailable, http.StatusTooManyRequests:
+		return errors.Join(ErrTryAgainLater, err)
+	}
+	codes, codesErr := hErr.ResultCodes()
+	if codesErr != nil || codes == nil {
+		return err
+	}
+	code := codes.TransactionCode
+	if codes.InnerTransactionCode != "" {
+		code = codes.InnerTransactionCode
+	}
+	switch code {
+	case "tx_bad_seq":
+		return errors.Join(ErrBadSequence, err)
+	case "tx_insufficient_fee":
+		return errors.Join(ErrInsufficientFee, err)
+	case "tx_too_late":
+		return errors.Join(ErrTxTooLate, err)
+	case "tx_failed":
+		return errors.Join(ErrTxFailed, err)
+	case "":
+		return err
+	}
+	return errors.Join(ErrTxRejected, err)
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/perun-stellar-backend/client"
+)
+
+func fastRetryPolicy() client.RetryPolicy {
+	return client.RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		TxValidity:     time.Minute,
+	}
+}
+
+type submittedTx struct {
+	hash string
+	fee  int64
+	seq  int64
+}
+
+// newSubmitBackend sets up a ContractBackend against a fake soroban-rpc that
+// answers sendTransaction with the given statuses in order.
+func newSubmitBackend(t *testing.T, fees client.FeeStrategy, sendResults ...client.RPCSendTxResponse,
+) (*client.ContractBackend, *fakeRPC, *[]submittedTx) {
+	kp := keypair.MustRandom()
+	rpc, url := newFakeRPC(t)
+	network := client.StandaloneNetwork()
+	network.SorobanRPCURL = url
+
+	rpc.handle("getLedgerEntries", func(json.RawMessage) interface{} {
+		return client.RPCGetLedgerEntriesResponse{
+			Entries: []client.RPCLedgerEntry{{XDR: accountEntryXdr(t, kp, 41)}},
+		}
+	})
+	txData, err := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+	require.NoError(t, err)
+	retXdr, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	require.NoError(t, err)
+	rpc.handle("simulateTransaction", func(json.RawMessage) interface{} {
+		return client.RPCSimulateTxResponse{
+			TransactionData: txData,
+			Results:         []client.RPCSimulateHostFunctionResult{{XDR: retXdr}},
+			MinResourceFee:  1000,
+		}
+	})
+	var submitted []submittedTx
+	rpc.handle("sendTransaction", func(params json.RawMessage) interface{} {
+		var req struct {
+			Transaction string `json:"transaction"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		generic, err := txnbuild.TransactionFromXDR(req.Transaction)
+		require.NoError(t, err)
+		tx, ok := generic.Transaction()
+		require.True(t, ok)
+		hash, err := tx.HashHex(network.Passphrase)
+		require.NoError(t, err)
+		submitted = append(submitted, submittedTx{hash: hash, fee: tx.BaseFee(), seq: tx.SequenceNumber()})
+
+		res := client.RPCSendTxResponse{Status: client.TxStatusPending}
+		if i := len(submitted) - 1; i < len(sendResults) {
+			res = sendResults[i]
+		}
+		res.Hash = hash
+		return res
+	})
+
+	sender := client.NewRPCSender(kp, network)
+	sender.SetPolling(time.Millisecond, 5)
+	cfg := client.TransactorConfig{}
+	cfg.SetKeyPair(kp)
+	cfg.SetNetwork(network)
+	cfg.SetSender(sender)
+	cfg.SetRetryPolicy(fastRetryPolicy())
+	if fees != nil {
+		cfg.SetFeeStrategy(fees)
+	}
+	return client.NewContractBackend(&cfg), rpc, &submitted
+}
+
+func resultXdr(t *testing.T, code xdr.TransactionResultCode) string {
+	res, err := xdr.MarshalBase64(xdr.TransactionResult{Result: xdr.TransactionResultResult{Code: code}})
+	require.NoError(t, err)
+	return res
+}
+
+func successMeta(t *testing.T) string {
+	meta, err := xdr.MarshalBase64(xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{}})
+	require.NoError(t, err)
+	return meta
+}
+
+var testContract = xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.Hash{1}}
+
+func TestSubmitTryAgainLaterResubmitsSameTx(t *testing.T) {
+	cb, rpc, submitted := newSubmitBackend(t, nil,
+		client.RPCSendTxResponse{Status: client.TxStatusTryAgainLater},
+		client.RPCSendTxResponse{Status: client.TxStatusTryAgainLater},
+	)
+	meta := successMeta(t)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+
+	_, err := cb.InvokeSignedTx(context.Background(), "close", xdr.ScVec{}, testContract)
+	require.NoError(t, err)
+	require.Len(t, *submitted, 3)
+	require.Equal(t, (*submitted)[0], (*submitted)[1])
+	require.Equal(t, (*submitted)[0], (*submitted)[2])
+}
+
+func TestSubmitInsufficientFeeEscalates(t *testing.T) {
+	fees := client.NewEscalatingFeeStrategy(client.NewFixedFeeStrategy(100, 0), 100)
+	cb, rpc, submitted := newSubmitBackend(t, fees,
+		client.RPCSendTxResponse{Status: client.TxStatusError, ErrorResultXdr: resultXdr(t, xdr.TransactionResultCodeTxInsufficientFee)},
+	)
+	meta := successMeta(t)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+
+	_, err := cb.InvokeSignedTx(context.Background(), "dispute", xdr.ScVec{}, testContract)
+	require.NoError(t, err)
+	require.Len(t, *submitted, 2)
+	require.Equal(t, int64(1100), (*submitted)[0].fee)
+	require.Equal(t, int64(1200), (*submitted)[1].fee)
+	require.Equal(t, (*submitted)[0].seq, (*submitted)[1].seq)
+	require.Equal(t, 1, rpc.count("getLedgerEntries"), "the sequence number must not be resynced")
+}
+
+func TestSubmitRejectedReleasesSequence(t *testing.T) {
+	cb, rpc, submitted := newSubmitBackend(t, nil,
+		client.RPCSendTxResponse{Status: client.TxStatusError, ErrorResultXdr: resultXdr(t, xdr.TransactionResultCodeTxBadAuth)},
+	)
+	meta := successMeta(t)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+
+	_, err := cb.InvokeSignedTx(context.Background(), "close", xdr.ScVec{}, testContract)
+	require.ErrorIs(t, err, client.ErrTxRejected)
+	_, err = cb.InvokeSignedTx(context.Background(), "close", xdr.ScVec{}, testContract)
+	require.NoError(t, err)
+	require.Len(t, *submitted, 2)
+	// The rejected transaction did not consume its sequence number, which is
+	// reused without resyncing.
+	require.Equal(t, (*submitted)[0].seq, (*submitted)[1].seq)
+	require.Equal(t, 1, rpc.count("getLedgerEntries"))
+}
+
+func TestSubmitFailedSurfacesStatus(t *testing.T) {
+	cb, rpc, submitted := newSubmitBackend(t, nil)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusFailed}
+	})
+
+	_, err := cb.InvokeSignedTx(context.Background(), "withdraw", xdr.ScVec{}, testContract)
+	require.ErrorIs(t, err, client.ErrTxFailed)
+	var txErr *client.TxError
+	require.ErrorAs(t, err, &txErr)
+	require.Equal(t, client.TxStatusFailed, txErr.Status)
+	require.Equal(t, (*submitted)[0].hash, txErr.Hash)
+	require.Equal(t, 1, txErr.Attempts)
+}
+
+func TestSubmitRetriesExhausted(t *testing.T) {
+	tryAgain := client.RPCSendTxResponse{Status: client.TxStatusTryAgainLater}
+	cb, _, submitted := newSubmitBackend(t, nil, tryAgain, tryAgain, tryAgain, tryAgain, tryAgain)
+
+	_, err := cb.InvokeSignedTx(context.Background(), "close", xdr.ScVec{}, testContract)
+	var txErr *client.TxError
+	require.ErrorAs(t, err, &txErr)
+	require.Equal(t, client.TxStatusTryAgainLater, txErr.Status)
+	require.Equal(t, 5, txErr.Attempts)
+	require.Len(t, *submitted, 5)
+}
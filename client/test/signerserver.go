@@ -0,0 +1,116 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/stellar/go/xdr"
+
+	"perun.network/perun-stellar-backend/client"
+)
+
+// SignerServer serves the remote signer protocol of client.RemoteSigner for
+// a TxSigner. It stands in for a signing service in tests.
+type SignerServer struct {
+	signer client.TxSigner
+	mux    *http.ServeMux
+}
+
+// NewSignerServer creates a new SignerServer signing with the given signer.
+func NewSignerServer(signer client.TxSigner) *SignerServer {
+	s := &SignerServer{signer: signer, mux: http.NewServeMux()}
+	s.mux.HandleFunc(client.SignerAddressPath, s.handleAddress)
+	s.mux.HandleFunc(client.SignerTxHashPath, s.handleSignTxHash)
+	s.mux.HandleFunc(client.SignerAuthEntryPath, s.handleSignAuthEntry)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *SignerServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *SignerServer) handleAddress(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, client.SignerAddressResponse{Address: s.signer.Address()})
+}
+
+func (s *SignerServer) handleSignTxHash(w http.ResponseWriter, r *http.Request) {
+	var req client.SignTxHashRequest
+	if !decodeSignRequest(w, r, &req) {
+		return
+	}
+	decoded, err := hex.DecodeString(req.Hash)
+	if err != nil || len(decoded) != 32 { //nolint:gomnd
+		http.Error(w, "invalid transaction hash", http.StatusBadRequest)
+		return
+	}
+	var hash [32]byte
+	copy(hash[:], decoded)
+	sig, err := s.signer.SignTxHash(r.Context(), hash)
+	writeSignature(w, sig, err)
+}
+
+func (s *SignerServer) handleSignAuthEntry(w http.ResponseWriter, r *http.Request) {
+	var req client.SignAuthEntryRequest
+	if !decodeSignRequest(w, r, &req) {
+		return
+	}
+	var preimage xdr.HashIdPreimage
+	if err := xdr.SafeUnmarshalBase64(req.Preimage, &preimage); err != nil {
+		http.Error(w, "invalid preimage", http.StatusBadRequest)
+		return
+	}
+	if preimage.Type != xdr.EnvelopeTypeEnvelopeTypeSorobanAuthorization {
+		http.Error(w, "preimage is not a soroban authorization", http.StatusBadRequest)
+		return
+	}
+	sig, err := s.signer.SignAuthEntry(r.Context(), preimage)
+	writeSignature(w, sig, err)
+}
+
+func decodeSignRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeSignature(w http.ResponseWriter, sig []byte, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, client.SignatureResponse{Signature: base64.StdEncoding.EncodeToString(sig)})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
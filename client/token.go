@@ -0,0 +1,227 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/stellar/go/xdr"
+	pchannel "perun.network/go-perun/channel"
+
+	"perun.network/perun-stellar-backend/channel/types"
+	"perun.network/perun-stellar-backend/wire"
+	"perun.network/perun-stellar-backend/wire/scval"
+)
+
+// TokenClient interacts with a token contract implementing the SEP-41 token
+// interface, e.g. a Stellar Asset Contract. Queries are simulated, transfers
+// and approvals are signed and submitted by the ContractBackend on behalf of
+// its account.
+type TokenClient struct {
+	cb    *ContractBackend
+	token xdr.ScAddress
+}
+
+// NewTokenClient creates a new TokenClient for the token contract at the
+// given address.
+func NewTokenClient(cb *ContractBackend, token xdr.ScAddress) *TokenClient {
+	return &TokenClient{cb: cb, token: token}
+}
+
+// Address returns the address of the token contract.
+func (t *TokenClient) Address() xdr.ScAddress {
+	return t.token
+}
+
+// Balance returns the balance of the given address.
+func (t *TokenClient) Balance(ctx context.Context, id xdr.ScAddress) (*big.Int, error) {
+	idVal, err := scval.WrapScAddress(id)
+	if err != nil {
+		return nil, err
+	}
+	return t.queryI128(ctx, "balance", idVal)
+}
+
+// Allowance returns the amount spender may transfer from the given address.
+func (t *TokenClient) Allowance(ctx context.Context, from, spender xdr.ScAddress) (*big.Int, error) {
+	fromVal, err := scval.WrapScAddress(from)
+	if err != nil {
+		return nil, err
+	}
+	spenderVal, err := scval.WrapScAddress(spender)
+	if err != nil {
+		return nil, err
+	}
+	return t.queryI128(ctx, "allowance", fromVal, spenderVal)
+}
+
+// Decimals returns the number of decimals of the token.
+func (t *TokenClient) Decimals(ctx context.Context) (uint32, error) {
+	val, err := t.cb.SimulateCall(ctx, "decimals", xdr.ScVec{}, t.token)
+	if err != nil {
+		return 0, err
+	}
+	decimals, ok := val.GetU32()
+	if !ok {
+		return 0, fmt.Errorf("decimals: expected u32, got %v", val.Type)
+	}
+	return uint32(decimals), nil
+}
+
+// Name returns the name of the token.
+func (t *TokenClient) Name(ctx context.Context) (string, error) {
+	return t.queryString(ctx, "name")
+}
+
+// Symbol returns the symbol of the token.
+func (t *TokenClient) Symbol(ctx context.Context) (string, error) {
+	return t.queryString(ctx, "symbol")
+}
+
+// Transfer transfers the amount from the account of the ContractBackend to
+// the given address.
+func (t *TokenClient) Transfer(ctx context.Context, to xdr.ScAddress, amount *big.Int) error {
+	from, err := t.cb.accountAddress()
+	if err != nil {
+		return err
+	}
+	args, err := tokenArgs(from, to, amount)
+	if err != nil {
+		return err
+	}
+	_, err = t.cb.InvokeSignedTx(ctx, "transfer", args, t.token)
+	return err
+}
+
+// Approve allows spender to transfer up to amount from the account of the
+// ContractBackend until the given ledger.
+func (t *TokenClient) Approve(ctx context.Context, spender xdr.ScAddress, amount *big.Int, expirationLedger uint32) error {
+	from, err := t.cb.accountAddress()
+	if err != nil {
+		return err
+	}
+	args, err := tokenArgs(from, spender, amount)
+	if err != nil {
+		return err
+	}
+	expiration, err := scval.WrapUint32(xdr.Uint32(expirationLedger))
+	if err != nil {
+		return err
+	}
+	_, err = t.cb.InvokeSignedTx(ctx, "approve", append(args, expiration), t.token)
+	return err
+}
+
+func (t *TokenClient) queryI128(ctx context.Context, fname string, args ...xdr.ScVal) (*big.Int, error) {
+	val, err := t.cb.SimulateCall(ctx, fname, args, t.token)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := ScValToBigInt(val)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fname, err)
+	}
+	return amount, nil
+}
+
+func (t *TokenClient) queryString(ctx context.Context, fname string) (string, error) {
+	val, err := t.cb.SimulateCall(ctx, fname, xdr.ScVec{}, t.token)
+	if err != nil {
+		return "", err
+	}
+	str, ok := val.GetStr()
+	if !ok {
+		return "", fmt.Errorf("%s: expected string, got %v", fname, val.Type)
+	}
+	return string(str), nil
+}
+
+// tokenArgs builds the arguments (from, to, amount) shared by transfer and
+// approve.
+func tokenArgs(from, to xdr.ScAddress, amount *big.Int) (xdr.ScVec, error) {
+	fromVal, err := scval.WrapScAddress(from)
+	if err != nil {
+		return nil, err
+	}
+	toVal, err := scval.WrapScAddress(to)
+	if err != nil {
+		return nil, err
+	}
+	parts, err := wire.MakeInt128Parts(amount)
+	if err != nil {
+		return nil, err
+	}
+	amountVal, err := scval.WrapInt128Parts(parts)
+	if err != nil {
+		return nil, err
+	}
+	return xdr.ScVec{fromVal, toVal, amountVal}, nil
+}
+
+// Int128ToBigInt converts a signed 128-bit integer to a big.Int.
+func Int128ToBigInt(parts xdr.Int128Parts) *big.Int {
+	i := big.NewInt(int64(parts.Hi))
+	i.Lsh(i, 64) //nolint:gomnd
+	return i.Add(i, new(big.Int).SetUint64(uint64(parts.Lo)))
+}
+
+// ScValToBigInt converts an ScVal holding an i128 to a big.Int.
+func ScValToBigInt(val xdr.ScVal) (*big.Int, error) {
+	parts, ok := val.GetI128()
+	if !ok {
+		return nil, fmt.Errorf("expected i128, got %v", val.Type)
+	}
+	return Int128ToBigInt(parts), nil
+}
+
+// AssetBalances returns the balances of the invoker's account in the given
+// assets. The balances of assets that are not Stellar assets are nil.
+func AssetBalances(ctx context.Context, inv Invoker, assets []pchannel.Asset) ([]*big.Int, error) {
+	bals := make([]*big.Int, len(assets))
+	for i, asset := range assets {
+		stellarAsset, ok := asset.(*types.StellarAsset)
+		if !ok {
+			continue
+		}
+		token, err := stellarAsset.MakeScAddress()
+		if err != nil {
+			return nil, err
+		}
+		if bals[i], err = inv.TokenBalance(ctx, token); err != nil {
+			return nil, err
+		}
+	}
+	return bals, nil
+}
+
+// TokenBalance returns the balance of the participant's account in the given token.
+func (c *ContractBackend) TokenBalance(ctx context.Context, token xdr.ScAddress) (*big.Int, error) {
+	account, err := c.accountAddress()
+	if err != nil {
+		return nil, err
+	}
+	return NewTokenClient(c, token).Balance(ctx, account)
+}
+
+// accountAddress returns the address of the participant's account.
+func (c *ContractBackend) accountAddress() (xdr.ScAddress, error) {
+	address, err := c.tr.GetAddress()
+	if err != nil {
+		return xdr.ScAddress{}, err
+	}
+	return accountScAddress(address)
+}
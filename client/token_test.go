@@ -0,0 +1,159 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/perun-stellar-backend/client"
+)
+
+// handleTokenCalls answers simulations of token functions with the given
+// results and records the arguments of the simulated calls.
+func handleTokenCalls(t *testing.T, rpc *fakeRPC, results map[string]xdr.ScVal) map[string]xdr.ScVec {
+	t.Helper()
+	txData, err := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+	require.NoError(t, err)
+	calls := make(map[string]xdr.ScVec)
+	rpc.handle("simulateTransaction", func(params json.RawMessage) interface{} {
+		var req struct {
+			Transaction string `json:"transaction"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		generic, err := txnbuild.TransactionFromXDR(req.Transaction)
+		require.NoError(t, err)
+		tx, ok := generic.Transaction()
+		require.True(t, ok)
+		call := tx.Operations()[0].(*txnbuild.InvokeHostFunction).HostFunction.InvokeContract
+		fname := string(call.FunctionName)
+		calls[fname] = call.Args
+
+		ret, ok := results[fname]
+		if !ok {
+			ret = xdr.ScVal{Type: xdr.ScValTypeScvVoid}
+		}
+		retXdr, err := xdr.MarshalBase64(ret)
+		require.NoError(t, err)
+		return client.RPCSimulateTxResponse{
+			TransactionData: txData,
+			Results:         []client.RPCSimulateHostFunctionResult{{XDR: retXdr}},
+			MinResourceFee:  1000,
+		}
+	})
+	return calls
+}
+
+func i128Val(hi int64, lo uint64) xdr.ScVal {
+	return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: xdr.Int64(hi), Lo: xdr.Uint64(lo)}}
+}
+
+func TestTokenClientQueries(t *testing.T) {
+	cb, rpc, submitted := newSubmitBackend(t, nil)
+	decimals := xdr.Uint32(7)
+	name := xdr.ScString("native")
+	symbol := xdr.ScString("XLM")
+	calls := handleTokenCalls(t, rpc, map[string]xdr.ScVal{
+		"balance":   i128Val(1, math.MaxUint64),
+		"allowance": i128Val(-1, 0),
+		"decimals":  {Type: xdr.ScValTypeScvU32, U32: &decimals},
+		"name":      {Type: xdr.ScValTypeScvString, Str: &name},
+		"symbol":    {Type: xdr.ScValTypeScvString, Str: &symbol},
+	})
+	token := client.NewTokenClient(cb, testContract)
+	ctx := context.Background()
+	holder := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.Hash{2}}
+
+	// 2^64 + 2^64 - 1 must not be truncated to a signed Lo.
+	balance, err := token.Balance(ctx, holder)
+	require.NoError(t, err)
+	want, ok := new(big.Int).SetString("36893488147419103231", 10)
+	require.True(t, ok)
+	require.Zero(t, want.Cmp(balance), "balance %v", balance)
+	require.Equal(t, holder, *calls["balance"][0].Address)
+
+	allowance, err := token.Allowance(ctx, holder, testContract)
+	require.NoError(t, err)
+	require.Zero(t, new(big.Int).Lsh(big.NewInt(-1), 64).Cmp(allowance), "allowance %v", allowance)
+	require.Len(t, calls["allowance"], 2)
+
+	d, err := token.Decimals(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint32(7), d)
+	n, err := token.Name(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "native", n)
+	s, err := token.Symbol(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "XLM", s)
+
+	require.Empty(t, *submitted, "queries must not submit transactions")
+}
+
+func TestTokenClientQueryTypeMismatch(t *testing.T) {
+	cb, rpc, _ := newSubmitBackend(t, nil)
+	handleTokenCalls(t, rpc, nil)
+	token := client.NewTokenClient(cb, testContract)
+
+	_, err := token.Balance(context.Background(), testContract)
+	require.Error(t, err)
+	_, err = token.Decimals(context.Background())
+	require.Error(t, err)
+	_, err = token.Symbol(context.Background())
+	require.Error(t, err)
+}
+
+func TestTokenClientTransferAndApprove(t *testing.T) {
+	cb, rpc, submitted := newSubmitBackend(t, nil)
+	calls := handleTokenCalls(t, rpc, nil)
+	meta := successMeta(t)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+	token := client.NewTokenClient(cb, testContract)
+	to := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.Hash{3}}
+	amount := new(big.Int).Lsh(big.NewInt(1), 63)
+
+	require.NoError(t, token.Transfer(context.Background(), to, amount))
+	args := calls["transfer"]
+	require.Len(t, args, 3)
+	require.Equal(t, xdr.ScAddressTypeScAddressTypeAccount, args[0].Address.Type)
+	require.Equal(t, to, *args[1].Address)
+	require.Zero(t, amount.Cmp(mustBigInt(t, args[2])))
+
+	require.NoError(t, token.Approve(context.Background(), to, amount, 1234))
+	args = calls["approve"]
+	require.Len(t, args, 4)
+	require.Equal(t, to, *args[1].Address)
+	require.Zero(t, amount.Cmp(mustBigInt(t, args[2])))
+	require.Equal(t, xdr.Uint32(1234), *args[3].U32)
+	require.Len(t, *submitted, 2)
+
+	require.Error(t, token.Transfer(context.Background(), to, big.NewInt(-1)))
+}
+
+func mustBigInt(t *testing.T, val xdr.ScVal) *big.Int {
+	t.Helper()
+	i, err := client.ScValToBigInt(val)
+	require.NoError(t, err)
+	return i
+}
@@ -4,11 +4,9 @@ import (
 	"context"
 	"errors"
 	"log"
-	"math/big"
 	"time"
 
 	"github.com/creachadair/jrpc2"
-	"github.com/creachadair/jrpc2/jhttp"
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/txnbuild"
@@ -17,14 +15,9 @@ import (
 	"perun.network/perun-stellar-backend/wire"
 )
 
-const (
-	sorobanRPCLink   = "http://localhost:8000/soroban/rpc"
-	sorobanTestnet   = "https://soroban-testnet.stellar.org"
-	horizonClientURL = "http://localhost:8000/"
-)
-
 // RPCGetTxResponse represents the type of the RPCGetTxResponse.
 type RPCGetTxResponse struct {
+	Status        string `json:"status"`
 	Error         string `json:"error,omitempty"`
 	EnvelopeXdr   string `json:"envelopeXdr"`
 	ResultXdr     string `json:"resultXdr"`
@@ -32,19 +25,13 @@ type RPCGetTxResponse struct {
 }
 
 //nolint:unused
-func (st *StellarSigner) createSignedTxFromParams(txParams txnbuild.TransactionParams) (*txnbuild.Transaction, error) {
+func (st *StellarSigner) createSignedTxFromParams(ctx context.Context, txParams txnbuild.TransactionParams) (*txnbuild.Transaction, error) {
 	txUnsigned, err := txnbuild.NewTransaction(txParams)
 	if err != nil {
 		return nil, err
 	}
 
-	var passphrase string
-	if st.hzClient.HorizonURL == horizonClientURL {
-		passphrase = NETWORK_PASSPHRASE
-	} else {
-		passphrase = NETWORK_PASSPHRASETestNet
-	}
-	tx, err := txUnsigned.Sign(passphrase, st.keyPair)
+	tx, err := signTx(ctx, st.signer, st.network.Passphrase, txUnsigned)
 	if err != nil {
 		return nil, err
 	}
@@ -53,47 +40,32 @@ func (st *StellarSigner) createSignedTxFromParams(txParams txnbuild.TransactionP
 }
 
 // DecodeTxMeta decodes the transaction meta from the transaction hash.
-func DecodeTxMeta(tx horizon.Transaction, hzClient *horizonclient.Client) (xdr.TransactionMeta, error) {
+func DecodeTxMeta(ctx context.Context, tx horizon.Transaction, hzClient *horizonclient.Client, network NetworkConfig) (xdr.TransactionMeta, error) {
 	// Before preflighting, make sure soroban-rpc is in sync with Horizon
-	root, err := hzClient.Root()
+	root, err := HorizonWithContext(ctx, hzClient).Root()
 	if err != nil {
 		log.Println("Error getting root", err)
 		return xdr.TransactionMeta{}, err
 	}
 
-	var link string
-	if hzClient.HorizonURL == horizonClientURL {
-		link = sorobanRPCLink
-	} else {
-		link = sorobanTestnet
-	}
-	err = syncWithSorobanRPC(uint32(root.HorizonSequence), link)
+	sorobanRPCClient := network.NewRPCClient()
+	defer sorobanRPCClient.Close()
+	err = syncWithSorobanRPC(ctx, uint32(root.HorizonSequence), sorobanRPCClient)
 	if err != nil {
 		log.Println("Error syncing with soroban-rpc", err)
 		return xdr.TransactionMeta{}, err
 	}
 
-	ch := jhttp.NewChannel(link, nil)
-	sorobanRPCClient := jrpc2.NewClient(ch, nil)
-
-	result := RPCGetTxResponse{}
-	err = sorobanRPCClient.CallResult(context.Background(), "getTransaction", struct {
-		Hash string `json:"hash"`
-	}{tx.Hash}, &result)
+	result, err := getTransaction(ctx, sorobanRPCClient, tx.Hash)
 	if err != nil {
 		log.Println("Error calling getTransaction", err)
 		return xdr.TransactionMeta{}, err
 	}
-	var transactionMeta xdr.TransactionMeta
-	err = xdr.SafeUnmarshalBase64(result.ResultMetaXdr, &transactionMeta)
-	if err != nil {
-		return xdr.TransactionMeta{}, err
-	}
-	return transactionMeta, nil
+	return decodeTxResult(result)
 }
 
 // BuildContractCallOp creates a txnbuild.InvokeHostFunction operation.
-func BuildContractCallOp(caller horizon.Account, fName xdr.ScSymbol, callArgs xdr.ScVec, contractIDAddress xdr.ScAddress) *txnbuild.InvokeHostFunction {
+func BuildContractCallOp(caller txnbuild.Account, fName xdr.ScSymbol, callArgs xdr.ScVec, contractIDAddress xdr.ScAddress) *txnbuild.InvokeHostFunction {
 	return &txnbuild.InvokeHostFunction{
 		HostFunction: xdr.HostFunction{
 			Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
@@ -103,7 +75,7 @@ func BuildContractCallOp(caller horizon.Account, fName xdr.ScSymbol, callArgs xd
 				Args:            callArgs,
 			},
 		},
-		SourceAccount: caller.AccountID,
+		SourceAccount: caller.GetAccountID(),
 	}
 }
 
@@ -113,6 +85,8 @@ type RPCSimulateTxResponse struct {
 	TransactionData string                          `json:"transactionData"`
 	Results         []RPCSimulateHostFunctionResult `json:"results"`
 	MinResourceFee  int64                           `json:"minResourceFee,string"`
+	Events          []string                        `json:"events,omitempty"`
+	RestorePreamble *RPCRestorePreamble             `json:"restorePreamble,omitempty"`
 }
 
 // RPCSimulateHostFunctionResult represents the return value of RPCSimulateHostFunctionResult.
@@ -122,10 +96,11 @@ type RPCSimulateHostFunctionResult struct {
 }
 
 // PreflightHostFunctions creates the fills the functions and calculates the minimal resource fee.
-func PreflightHostFunctions(hzClient *horizonclient.Client,
+// If hzClient is nil, the simulation is sent to soroban-rpc without syncing with Horizon first.
+func PreflightHostFunctions(ctx context.Context, hzClient *horizonclient.Client, network NetworkConfig,
 	sourceAccount txnbuild.Account, function txnbuild.InvokeHostFunction,
 ) (txnbuild.InvokeHostFunction, int64, error) {
-	result, transactionData, err := simulateTransaction(hzClient, sourceAccount, &function)
+	result, transactionData, err := simulateTransaction(ctx, hzClient, network, sourceAccount, &function)
 	if err != nil {
 		return txnbuild.InvokeHostFunction{}, 0, err
 	}
@@ -152,16 +127,20 @@ func PreflightHostFunctions(hzClient *horizonclient.Client,
 			funAuth = append(funAuth, authEntry)
 		}
 	}
-	function.Auth = funAuth
+	// Entries that were already present, e.g. because they were signed after a
+	// first simulation, are kept as they are.
+	if len(function.Auth) == 0 {
+		function.Auth = funAuth
+	}
 
 	return function, result.MinResourceFee, nil
 }
 
 // PreflightHostFunctionsResult simulates a transaction to get the minimum fee and result for a host function.
-func PreflightHostFunctionsResult(hzClient *horizonclient.Client,
+func PreflightHostFunctionsResult(ctx context.Context, hzClient *horizonclient.Client, network NetworkConfig,
 	sourceAccount txnbuild.Account, function txnbuild.InvokeHostFunction, chInfo bool,
 ) (wire.Channel, string, txnbuild.InvokeHostFunction, int64, error) {
-	result, transactionData, err := simulateTransaction(hzClient, sourceAccount, &function)
+	result, transactionData, err := simulateTransaction(ctx, hzClient, network, sourceAccount, &function)
 	if err != nil {
 		return wire.Channel{}, "", txnbuild.InvokeHostFunction{}, 0, err
 	}
@@ -196,39 +175,33 @@ func PreflightHostFunctionsResult(hzClient *horizonclient.Client,
 
 		return getChan, "", function, result.MinResourceFee, nil
 	}
-	i128 := decodedXdr.MustI128()
-	hi := big.NewInt(int64(i128.Hi))
-	lo := big.NewInt(int64(i128.Lo))
-
-	// Combine hi and lo into a single big.Int
-	combined := hi.Lsh(hi, 64).Or(hi, lo) //nolint:gomnd
-	return wire.Channel{}, combined.String(), function, result.MinResourceFee, nil
+	amount, err := ScValToBigInt(decodedXdr)
+	if err != nil {
+		return wire.Channel{}, "", function, result.MinResourceFee, err
+	}
+	return wire.Channel{}, amount.String(), function, result.MinResourceFee, nil
 }
 
-func simulateTransaction(hzClient *horizonclient.Client,
+func simulateTransaction(ctx context.Context, hzClient *horizonclient.Client, network NetworkConfig,
 	sourceAccount txnbuild.Account, op txnbuild.Operation,
 ) (RPCSimulateTxResponse, xdr.SorobanTransactionData, error) {
-	// Before preflighting, make sure soroban-rpc is in sync with Horizon
-	root, err := hzClient.Root()
-	if err != nil {
-		log.Println("Error getting root", err)
-		return RPCSimulateTxResponse{}, xdr.SorobanTransactionData{}, err
-	}
+	sorobanRPCClient := network.NewRPCClient()
+	defer sorobanRPCClient.Close()
+	// Before preflighting, make sure soroban-rpc is in sync with Horizon. Without
+	// a horizon client, soroban-rpc is the only source of truth and no sync is needed.
+	if hzClient != nil {
+		root, err := HorizonWithContext(ctx, hzClient).Root()
+		if err != nil {
+			log.Println("Error getting root", err)
+			return RPCSimulateTxResponse{}, xdr.SorobanTransactionData{}, err
+		}
 
-	var link string
-	if hzClient.HorizonURL == horizonClientURL {
-		link = sorobanRPCLink
-	} else {
-		link = sorobanTestnet
-	}
-	err = syncWithSorobanRPC(uint32(root.HorizonSequence), link)
-	if err != nil {
-		log.Println("Error syncing with soroban-rpc", err)
-		return RPCSimulateTxResponse{}, xdr.SorobanTransactionData{}, err
+		err = syncWithSorobanRPC(ctx, uint32(root.HorizonSequence), sorobanRPCClient)
+		if err != nil {
+			log.Println("Error syncing with soroban-rpc", err)
+			return RPCSimulateTxResponse{}, xdr.SorobanTransactionData{}, err
+		}
 	}
-
-	ch := jhttp.NewChannel(link, nil)
-	sorobanRPCClient := jrpc2.NewClient(ch, nil)
 	txParams := GetBaseTransactionParamsWithFee(sourceAccount, txnbuild.MinBaseFee, op)
 	txParams.IncrementSequenceNum = false
 	tx, err := txnbuild.NewTransaction(txParams)
@@ -241,13 +214,19 @@ func simulateTransaction(hzClient *horizonclient.Client,
 		return RPCSimulateTxResponse{}, xdr.SorobanTransactionData{}, err
 	}
 	result := RPCSimulateTxResponse{}
-	err = sorobanRPCClient.CallResult(context.Background(), "simulateTransaction", struct {
+	err = sorobanRPCClient.CallResult(ctx, "simulateTransaction", struct {
 		Transaction string `json:"transaction"`
 	}{base64}, &result)
 	if err != nil {
 		log.Println("Error calling simulateTransaction", err)
 		return RPCSimulateTxResponse{}, xdr.SorobanTransactionData{}, err
 	}
+	if result.Error != "" {
+		return RPCSimulateTxResponse{}, xdr.SorobanTransactionData{}, simulationError(result)
+	}
+	if result.RestorePreamble != nil {
+		return RPCSimulateTxResponse{}, xdr.SorobanTransactionData{}, restoreRequiredError(*result.RestorePreamble)
+	}
 	var transactionData xdr.SorobanTransactionData
 	err = xdr.SafeUnmarshalBase64(result.TransactionData, &transactionData)
 	if err != nil {
@@ -257,21 +236,20 @@ func simulateTransaction(hzClient *horizonclient.Client,
 	return result, transactionData, nil
 }
 
-func syncWithSorobanRPC(ledgerToWaitFor uint32, url string) error {
+func syncWithSorobanRPC(ctx context.Context, ledgerToWaitFor uint32, sorobanRPCClient *jrpc2.Client) error {
 	for j := 0; j < 20; j++ {
-		result := struct {
-			Sequence uint32 `json:"sequence"`
-		}{}
-		ch := jhttp.NewChannel(url, nil)
-		sorobanRPCClient := jrpc2.NewClient(ch, nil)
-		err := sorobanRPCClient.CallResult(context.Background(), "getLatestLedger", nil, &result)
+		latest, err := getLatestLedger(ctx, sorobanRPCClient)
 		if err != nil {
 			return err
 		}
-		if result.Sequence >= ledgerToWaitFor {
+		if latest >= ledgerToWaitFor {
 			return nil
 		}
-		time.Sleep(500 * time.Millisecond) //nolint:gomnd
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond): //nolint:gomnd
+		}
 	}
 	return errors.New("time out waiting for soroban-rpc to sync")
 }
@@ -1,8 +1,12 @@
 package client
 
 import (
+	"context"
+	"errors"
+
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/txnbuild"
 	"github.com/stellar/go/xdr"
 )
@@ -26,13 +30,19 @@ func CreateSignedTransactionWithParams(signers []*keypair.Full, txParams txnbuil
 
 // TxSender is a struct that implements the Sender interface.
 type TxSender struct {
-	kp       *keypair.Full
+	signer   TxSigner
 	hzClient *horizonclient.Client
+	network  NetworkConfig
 }
 
-// NewSender creates a new TxSender.
+// NewSender creates a new TxSender signing with the given keypair.
 func NewSender(kp *keypair.Full, hzClient *horizonclient.Client) Sender {
-	return &TxSender{kp: kp, hzClient: hzClient}
+	return &TxSender{signer: NewKeypairSigner(kp), hzClient: hzClient}
+}
+
+// SetSigner sets the signer of the transactions.
+func (s *TxSender) SetSigner(signer TxSigner) {
+	s.signer = signer
 }
 
 // SetHzClient sets the horizon client.
@@ -40,26 +50,58 @@ func (s *TxSender) SetHzClient(hzClient *horizonclient.Client) {
 	s.hzClient = hzClient
 }
 
+// SetNetwork sets the network the sender signs for and queries the
+// transaction result from.
+func (s *TxSender) SetNetwork(network NetworkConfig) {
+	s.network = network
+}
+
+// getNetwork returns the configured network, falling back to the preset
+// matching the horizon client if none was set.
+func (s *TxSender) getNetwork() (NetworkConfig, error) {
+	if s.network.IsZero() {
+		return NetworkFromHorizonURL(s.hzClient.HorizonURL)
+	}
+	return s.network, nil
+}
+
 // SignSendTx signs and sends the transaction.
-func (s *TxSender) SignSendTx(txUnsigned txnbuild.Transaction) (xdr.TransactionMeta, error) {
-	var passphrase string
-	if s.hzClient.HorizonURL == horizonClientURL {
-		passphrase = NETWORK_PASSPHRASE
-	} else {
-		passphrase = NETWORK_PASSPHRASETestNet
+func (s *TxSender) SignSendTx(ctx context.Context, txUnsigned txnbuild.Transaction) (xdr.TransactionMeta, error) {
+	network, err := s.getNetwork()
+	if err != nil {
+		return xdr.TransactionMeta{}, err
 	}
-	tx, err := txUnsigned.Sign(passphrase, s.kp)
+	tx, err := signTx(ctx, s.signer, network.Passphrase, &txUnsigned)
 	if err != nil {
 		return xdr.TransactionMeta{}, err
 	}
+	return s.SendSignedTx(ctx, tx)
+}
+
+// SendSignedTx submits an already signed transaction to Horizon.
+func (s *TxSender) SendSignedTx(ctx context.Context, tx *txnbuild.Transaction) (xdr.TransactionMeta, error) {
+	txSent, err := HorizonWithContext(ctx, s.hzClient).SubmitTransaction(tx)
+	return s.decodeSubmission(ctx, txSent, err)
+}
 
-	txSent, err := s.hzClient.SubmitTransaction(tx)
+// SendFeeBumpTx submits a signed fee-bump transaction to Horizon.
+func (s *TxSender) SendFeeBumpTx(ctx context.Context, tx *txnbuild.FeeBumpTransaction) (xdr.TransactionMeta, error) {
+	txSent, err := HorizonWithContext(ctx, s.hzClient).SubmitFeeBumpTransaction(tx)
+	return s.decodeSubmission(ctx, txSent, err)
+}
+
+// decodeSubmission decodes the transaction meta of a transaction submitted to Horizon.
+func (s *TxSender) decodeSubmission(ctx context.Context, txSent horizon.Transaction, err error) (xdr.TransactionMeta, error) {
+	if err != nil {
+		return xdr.TransactionMeta{}, classifyHorizonError(err)
+	}
+	network, err := s.getNetwork()
 	if err != nil {
 		return xdr.TransactionMeta{}, err
 	}
-	txMeta, err := DecodeTxMeta(txSent, s.hzClient)
+	txMeta, err := DecodeTxMeta(ctx, txSent, s.hzClient, network)
 	if err != nil {
-		return xdr.TransactionMeta{}, ErrCouldNotDecodeTxMeta
+		return xdr.TransactionMeta{}, errors.Join(ErrCouldNotDecodeTxMeta, err)
 	}
 	_ = txMeta.V3.SorobanMeta.ReturnValue
 
@@ -0,0 +1,343 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	pchannel "perun.network/go-perun/channel"
+)
+
+// MaxKeysPerTTLExtension is the maximum number of ledger entries extended by
+// a single ExtendFootprintTTL transaction.
+const MaxKeysPerTTLExtension = 40
+
+// extendFunction is the name under which TTL extensions are passed to the
+// FeeStrategy.
+const extendFunction = "extend_footprint_ttl"
+
+// TTLKeeperConfig configures a TTLKeeper. Durations are given in ledgers,
+// which close roughly every five seconds.
+type TTLKeeperConfig struct {
+	// SafetyMargin is the number of ledgers before expiry at which a channel
+	// entry is extended.
+	SafetyMargin uint32
+	// ExtendTo is the number of ledgers an expiring entry stays live after
+	// its extension.
+	ExtendTo uint32
+	// Interval is the time between two checks of Run.
+	Interval time.Duration
+}
+
+// DefaultTTLKeeperConfig returns a config that extends channel entries a day
+// before they expire, such that they live for another 30 days.
+func DefaultTTLKeeperConfig() TTLKeeperConfig {
+	return TTLKeeperConfig{
+		SafetyMargin: 17_280,           //nolint:gomnd
+		ExtendTo:     518_400,          //nolint:gomnd
+		Interval:     10 * time.Minute, //nolint:gomnd
+	}
+}
+
+// TTLEntry is the kind of a ledger entry kept alive by a TTLKeeper.
+type TTLEntry int
+
+const (
+	// TTLEntryChannel is the entry of a channel.
+	TTLEntryChannel TTLEntry = iota
+	// TTLEntryInstance is the instance of the Perun contract.
+	TTLEntryInstance
+	// TTLEntryCode is the wasm code of the Perun contract.
+	TTLEntryCode
+)
+
+// TTLStatus is the time-to-live of a ledger entry.
+type TTLStatus struct {
+	Entry TTLEntry
+	Key   xdr.LedgerKey
+	// Channel is the ID of the channel of a TTLEntryChannel.
+	Channel         pchannel.ID
+	LiveUntilLedger uint32
+	// Remaining is the number of ledgers until the entry is archived.
+	Remaining uint32
+	// Archived reports whether the entry already expired. Archived entries
+	// are restored by the next invocation that touches them.
+	Archived bool
+	// Expiring reports whether the entry expires within the safety margin.
+	Expiring bool
+}
+
+// TTLKeeper keeps the Perun contract and the ledger entries of open channels
+// alive by extending their time-to-live before they are archived. Besides
+// the channels, the instance and the wasm code of the contract are extended,
+// without which no channel can be concluded.
+type TTLKeeper struct {
+	cb        *ContractBackend
+	perunAddr xdr.ScAddress
+	cfg       TTLKeeperConfig
+
+	mu       sync.Mutex
+	channels map[pchannel.ID]struct{}
+}
+
+// NewTTLKeeper creates a new TTLKeeper for the channels of the given Perun contract.
+func NewTTLKeeper(cb *ContractBackend, perunAddr xdr.ScAddress, cfg TTLKeeperConfig) *TTLKeeper {
+	return &TTLKeeper{
+		cb:        cb,
+		perunAddr: perunAddr,
+		cfg:       cfg,
+		channels:  make(map[pchannel.ID]struct{}),
+	}
+}
+
+// Track adds the channel to the channels kept alive.
+func (k *TTLKeeper) Track(id pchannel.ID) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.channels[id] = struct{}{}
+}
+
+// Untrack removes the channel from the channels kept alive.
+func (k *TTLKeeper) Untrack(id pchannel.ID) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.channels, id)
+}
+
+// Channels returns the IDs of the tracked channels.
+func (k *TTLKeeper) Channels() []pchannel.ID {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	ids := make([]pchannel.ID, 0, len(k.channels))
+	for id := range k.channels {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Report returns the TTL of the contract instance, the contract code and all
+// tracked channels that are stored on the ledger, ordered by their expiry.
+// Channels that are no longer stored, e.g. because they were withdrawn, are
+// omitted.
+func (k *TTLKeeper) Report(ctx context.Context) ([]TTLStatus, error) {
+	rpc := k.cb.tr.network.NewRPCClient()
+	defer rpc.Close()
+
+	report, err := k.contractReport(ctx, rpc)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := k.Channels()
+	keys := make(map[string]int, len(ids))
+	ledgerKeys := make([]xdr.LedgerKey, 0, len(ids))
+	for i, id := range ids {
+		key, err := ChannelLedgerKey(k.perunAddr, id)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := xdr.MarshalBase64(key)
+		if err != nil {
+			return nil, err
+		}
+		keys[encoded] = i
+		ledgerKeys = append(ledgerKeys, key)
+	}
+	for start := 0; start < len(ledgerKeys); start += MaxLedgerKeysPerRequest {
+		end := min(start+MaxLedgerKeysPerRequest, len(ledgerKeys))
+		result, err := getLedgerEntries(ctx, rpc, ledgerKeys[start:end]...)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range result.Entries {
+			i, ok := keys[entry.Key]
+			if !ok {
+				continue
+			}
+			status := k.status(entry.LiveUntilLedgerSeq, result.LatestLedger)
+			status.Key, status.Channel = ledgerKeys[i], ids[i]
+			report = append(report, status)
+		}
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].LiveUntilLedger < report[j].LiveUntilLedger
+	})
+	return report, nil
+}
+
+// contractReport returns the TTL of the instance and the wasm code of the
+// Perun contract. The code is read from the instance, so it is omitted if
+// the instance is not stored on the ledger.
+func (k *TTLKeeper) contractReport(ctx context.Context, rpc *jrpc2.Client) ([]TTLStatus, error) {
+	instanceKey := ContractInstanceLedgerKey(k.perunAddr)
+	result, err := getLedgerEntries(ctx, rpc, instanceKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Entries) == 0 {
+		return nil, nil
+	}
+	entry := result.Entries[0]
+	instance := k.status(entry.LiveUntilLedgerSeq, result.LatestLedger)
+	instance.Entry, instance.Key = TTLEntryInstance, instanceKey
+	report := []TTLStatus{instance}
+
+	var data xdr.LedgerEntryData
+	if err := xdr.SafeUnmarshalBase64(entry.XDR, &data); err != nil {
+		return nil, err
+	}
+	contractData, ok := data.GetContractData()
+	if !ok || contractData.Val.Instance == nil || contractData.Val.Instance.Executable.WasmHash == nil {
+		return report, nil
+	}
+	codeKey := ContractCodeLedgerKey(*contractData.Val.Instance.Executable.WasmHash)
+	result, err = getLedgerEntries(ctx, rpc, codeKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Entries) > 0 {
+		code := k.status(result.Entries[0].LiveUntilLedgerSeq, result.LatestLedger)
+		code.Entry, code.Key = TTLEntryCode, codeKey
+		report = append(report, code)
+	}
+	return report, nil
+}
+
+func (k *TTLKeeper) status(liveUntil, latest uint32) TTLStatus {
+	status := TTLStatus{LiveUntilLedger: liveUntil}
+	if liveUntil < latest {
+		status.Archived = true
+		status.Expiring = true
+		return status
+	}
+	status.Remaining = liveUntil - latest
+	status.Expiring = status.Remaining <= k.cfg.SafetyMargin
+	return status
+}
+
+// ExtendExpiring extends the TTL of all live entries that expire within the
+// safety margin. It returns the statuses of the extended entries.
+func (k *TTLKeeper) ExtendExpiring(ctx context.Context) ([]TTLStatus, error) {
+	report, err := k.Report(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		extended []TTLStatus
+		keys     []xdr.LedgerKey
+	)
+	for _, status := range report {
+		if !status.Expiring || status.Archived {
+			continue
+		}
+		extended = append(extended, status)
+		keys = append(keys, status.Key)
+	}
+	for start := 0; start < len(keys); start += MaxKeysPerTTLExtension {
+		end := min(start+MaxKeysPerTTLExtension, len(keys))
+		if err := k.cb.ExtendTTL(ctx, keys[start:end], k.cfg.ExtendTo); err != nil {
+			return extended[:start], err
+		}
+	}
+	return extended, nil
+}
+
+// Run extends expiring entries periodically until the context is done.
+// Failed extensions are logged and retried in the next round.
+func (k *TTLKeeper) Run(ctx context.Context) error {
+	ticker := time.NewTicker(k.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if extended, err := k.ExtendExpiring(ctx); err != nil {
+			log.Println("Error extending TTLs:", err)
+		} else if len(extended) > 0 {
+			log.Printf("Extended TTL of %d ledger entries", len(extended))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExtendTTL extends the time-to-live of the given ledger entries such that
+// they stay live for extendTo more ledgers. The entries must not be archived.
+// Like contract invocations, the extension is submitted by the relayer or
+// from a channel account of the pool if configured, and sponsored if a fee
+// sponsor is set.
+func (c *ContractBackend) ExtendTTL(ctx context.Context, keys []xdr.LedgerKey, extendTo uint32) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if c.tr.relayer != nil {
+		return c.tr.relayer.ExtendTTL(ctx, keys, extendTo)
+	}
+	if c.tr.pool != nil {
+		source, err := c.tr.pool.pick()
+		if err != nil {
+			return err
+		}
+		send, err := c.poolSend(source)
+		if err != nil {
+			return err
+		}
+		return c.extendTTL(ctx, source.seq, send, keys, extendTo)
+	}
+	send, err := c.defaultSend()
+	if err != nil {
+		return err
+	}
+	return c.extendTTL(ctx, c.tr.seq, send, keys, extendTo)
+}
+
+// extendTTL extends the time-to-live of the ledger entries from the account
+// of seq.
+func (c *ContractBackend) extendTTL(ctx context.Context, seq *SequenceManager, send signSendFunc, keys []xdr.LedgerKey, extendTo uint32) error {
+	acc, err := seq.Account(ctx)
+	if err != nil {
+		return errors.Join(errors.New("failed to load source account"), err)
+	}
+
+	op := &txnbuild.ExtendFootprintTtl{
+		ExtendTo:      extendTo,
+		SourceAccount: acc.GetAccountID(),
+		Ext: xdr.TransactionExt{
+			V: 1,
+			SorobanData: &xdr.SorobanTransactionData{
+				Resources: xdr.SorobanResources{
+					Footprint: xdr.LedgerFootprint{ReadOnly: keys},
+				},
+			},
+		},
+	}
+	result, transactionData, err := simulateTransaction(ctx, c.tr.syncHorizonClient(), c.tr.network, acc, op)
+	if err != nil {
+		return err
+	}
+	op.Ext.SorobanData = &transactionData
+
+	feeReq := FeeRequest{Function: extendFunction, ResourceFee: result.MinResourceFee}
+	_, err = c.submit(ctx, seq, send, feeReq, op)
+	return err
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+	pchannel "perun.network/go-perun/channel"
+
+	"perun.network/perun-stellar-backend/client"
+)
+
+const testLatestLedger = 1000
+
+func TestTTLKeeper(t *testing.T) {
+	cb, rpc, _ := newSubmitBackend(t, nil)
+	expiring, live, archived, closed := pchannel.ID{1}, pchannel.ID{2}, pchannel.ID{3}, pchannel.ID{4}
+	liveUntil := map[pchannel.ID]uint32{
+		expiring: testLatestLedger + 50,
+		live:     testLatestLedger + 5000,
+		archived: testLatestLedger - 1,
+	}
+	account := accountEntryXdr(t, keypair.MustRandom(), 41)
+	wasmHash := xdr.Hash{9}
+	instanceKey, codeKey := client.ContractInstanceLedgerKey(testContract), client.ContractCodeLedgerKey(wasmHash)
+	instance, err := xdr.MarshalBase64(xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.ContractDataEntry{
+			Contract:   testContract,
+			Key:        instanceKey.ContractData.Key,
+			Durability: xdr.ContractDataDurabilityPersistent,
+			Val: xdr.ScVal{Type: xdr.ScValTypeScvContractInstance, Instance: &xdr.ScContractInstance{
+				Executable: xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableWasm, WasmHash: &wasmHash},
+			}},
+		},
+	})
+	require.NoError(t, err)
+	rpc.handle("getLedgerEntries", func(params json.RawMessage) interface{} {
+		var req struct {
+			Keys []string `json:"keys"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		res := client.RPCGetLedgerEntriesResponse{LatestLedger: testLatestLedger}
+		for _, encoded := range req.Keys {
+			var key xdr.LedgerKey
+			require.NoError(t, xdr.SafeUnmarshalBase64(encoded, &key))
+			switch {
+			case key.Type == xdr.LedgerEntryTypeAccount:
+				res.Entries = append(res.Entries, client.RPCLedgerEntry{Key: encoded, XDR: account})
+				continue
+			case key.Equals(instanceKey):
+				res.Entries = append(res.Entries, client.RPCLedgerEntry{Key: encoded, XDR: instance, LiveUntilLedgerSeq: testLatestLedger + 30})
+				continue
+			case key.Equals(codeKey):
+				res.Entries = append(res.Entries, client.RPCLedgerEntry{Key: encoded, LiveUntilLedgerSeq: testLatestLedger + 7000})
+				continue
+			}
+			for id, until := range liveUntil {
+				chKey, err := client.ChannelLedgerKey(testContract, id)
+				require.NoError(t, err)
+				if key.Equals(chKey) {
+					res.Entries = append(res.Entries, client.RPCLedgerEntry{Key: encoded, LiveUntilLedgerSeq: until})
+				}
+			}
+		}
+		return res
+	})
+	var extendedKeys []xdr.LedgerKey
+	rpc.handle("sendTransaction", func(params json.RawMessage) interface{} {
+		var req struct {
+			Transaction string `json:"transaction"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		generic, err := txnbuild.TransactionFromXDR(req.Transaction)
+		require.NoError(t, err)
+		tx, ok := generic.Transaction()
+		require.True(t, ok)
+		require.IsType(t, &txnbuild.ExtendFootprintTtl{}, tx.Operations()[0])
+		extendedKeys = append(extendedKeys, tx.ToXDR().V1.Tx.Ext.SorobanData.Resources.Footprint.ReadOnly...)
+		return client.RPCSendTxResponse{Status: client.TxStatusPending}
+	})
+	meta := successMeta(t)
+	rpc.handle("getTransaction", func(json.RawMessage) interface{} {
+		return client.RPCGetTxResponse{Status: client.TxStatusSuccess, ResultMetaXdr: meta}
+	})
+	// The simulation returns the footprint of the extension as is.
+	rpc.handle("simulateTransaction", func(params json.RawMessage) interface{} {
+		var req struct {
+			Transaction string `json:"transaction"`
+		}
+		require.NoError(t, json.Unmarshal(params, &req))
+		generic, err := txnbuild.TransactionFromXDR(req.Transaction)
+		require.NoError(t, err)
+		tx, ok := generic.Transaction()
+		require.True(t, ok)
+		txData, err := xdr.MarshalBase64(tx.ToXDR().V1.Tx.Ext.SorobanData)
+		require.NoError(t, err)
+		return client.RPCSimulateTxResponse{TransactionData: txData, MinResourceFee: 100}
+	})
+
+	keeper := client.NewTTLKeeper(cb, testContract, client.TTLKeeperConfig{SafetyMargin: 100, ExtendTo: 10_000})
+	for _, id := range []pchannel.ID{expiring, live, archived, closed} {
+		keeper.Track(id)
+	}
+
+	chKey := func(id pchannel.ID) xdr.LedgerKey {
+		key, err := client.ChannelLedgerKey(testContract, id)
+		require.NoError(t, err)
+		return key
+	}
+	report, err := keeper.Report(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []client.TTLStatus{
+		{Key: chKey(archived), Channel: archived, LiveUntilLedger: testLatestLedger - 1, Archived: true, Expiring: true},
+		{Entry: client.TTLEntryInstance, Key: instanceKey, LiveUntilLedger: testLatestLedger + 30, Remaining: 30, Expiring: true},
+		{Key: chKey(expiring), Channel: expiring, LiveUntilLedger: testLatestLedger + 50, Remaining: 50, Expiring: true},
+		{Key: chKey(live), Channel: live, LiveUntilLedger: testLatestLedger + 5000, Remaining: 5000},
+		{Entry: client.TTLEntryCode, Key: codeKey, LiveUntilLedger: testLatestLedger + 7000, Remaining: 7000},
+	}, report)
+
+	extended, err := keeper.ExtendExpiring(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, report[1:3], extended)
+	require.Equal(t, []xdr.LedgerKey{instanceKey, chKey(expiring)}, extendedKeys)
+
+	keeper.Untrack(expiring)
+	require.Len(t, keeper.Channels(), 3)
+}
@@ -59,6 +59,7 @@ var (
 	ErrNotStellarPerunContract = errors.New("event was not from a Perun payment channel contract")
 	ErrEventUnsupported        = errors.New("this type of event is unsupported")
 	ErrEventIntegrity          = errors.New("contract ID does not match payment channel + passphrase")
+	ErrNoSorobanMeta           = errors.New("transaction meta does not contain soroban events")
 	ErrNoFundEvent             = errors.New("fund event not found")
 	ErrNoCloseEvent            = errors.New("close event not found")
 	ErrNoWithdrawEvent         = errors.New("withdraw event not found")
@@ -118,6 +119,31 @@ type (
 		versionV Version
 		timeout  pchannel.Timeout
 	}
+
+	// FundedEvent is emitted when a channel is funded by both parties.
+	FundedEvent struct {
+		channel  wire.Channel
+		idv      pchannel.ID
+		versionV Version
+		timeout  pchannel.Timeout
+	}
+
+	// ForceClosedEvent is emitted when a channel is force closed after the
+	// challenge duration of a dispute.
+	ForceClosedEvent struct {
+		channel  wire.Channel
+		idv      pchannel.ID
+		versionV Version
+		timeout  pchannel.Timeout
+	}
+
+	// WithdrawingEvent is emitted when a party withdraws from a channel.
+	WithdrawingEvent struct {
+		channel  wire.Channel
+		idv      pchannel.ID
+		versionV Version
+		timeout  pchannel.Timeout
+	}
 )
 
 // StellarEvent is a struct that represents a Stellar event.
@@ -297,131 +323,220 @@ func (e *DisputedEvent) SetID(id pchannel.ID) {
 	e.idv = id
 }
 
-// DecodeEventsPerun decodes the events from a Stellar transaction meta data.
-//
-//nolint:funlen
-func DecodeEventsPerun(txMeta xdr.TransactionMeta) ([]PerunEvent, error) {
-	evs := make([]PerunEvent, 0)
+// GetChannel returns the channel of the FundedEvent.
+func (e *FundedEvent) GetChannel() wire.Channel {
+	return e.channel
+}
 
-	txEvents := txMeta.V3.SorobanMeta.Events
+// GetType returns the type of the FundedEvent.
+func (e *FundedEvent) GetType() (EventType, error) {
+	return EventTypeFundedChannel, nil
+}
 
-	for _, ev := range txEvents {
-		sev := StellarEvent{}
-		topics := ev.Body.V0.Topics
+// ID returns the ID of the FundedEvent.
+func (e *FundedEvent) ID() pchannel.ID {
+	return e.idv
+}
 
-		if len(topics) < 2 { //nolint:gomnd
-			return nil, ErrNotStellarPerunContract
-		}
-		perunString, ok := topics[0].GetSym()
+// Version returns the version of the FundedEvent.
+func (e *FundedEvent) Version() Version {
+	return e.versionV
+}
 
-		if perunString == "transfer" {
-			continue
-		}
+// Timeout returns the timeout of the FundedEvent.
+func (e *FundedEvent) Timeout() pchannel.Timeout {
+	return e.timeout
+}
 
-		if perunString != AssertPerunSymbol {
-			return nil, ErrNotStellarPerunContract
-		}
-		if !ok {
-			return nil, ErrNotStellarPerunContract
-		}
+// SetID sets the ID of the FundedEvent.
+func (e *FundedEvent) SetID(id pchannel.ID) {
+	e.idv = id
+}
 
-		fn, ok := topics[1].GetSym()
-		if !ok {
-			return nil, ErrNotStellarPerunContract
-		}
+// GetChannel returns the channel of the ForceClosedEvent.
+func (e *ForceClosedEvent) GetChannel() wire.Channel {
+	return e.channel
+}
 
-		eventType, found := STELLAR_PERUN_CHANNEL_CONTRACT_TOPICS[fn]
-		if !found {
-			return nil, ErrNotStellarPerunContract
-		}
-		sev.Type = eventType
+// GetType returns the type of the ForceClosedEvent.
+func (e *ForceClosedEvent) GetType() (EventType, error) {
+	return EventTypeForceClose, nil
+}
 
-		switch sev.GetType() {
-		case EventTypeOpen:
-			log.Println("Open Event received", sev)
-			openEventchanStellar, err := GetChannelFromEvents(ev.Body.V0.Data)
-			if err != nil {
-				return nil, err
-			}
+// ID returns the ID of the ForceClosedEvent.
+func (e *ForceClosedEvent) ID() pchannel.ID {
+	return e.idv
+}
 
-			controlsOpen := initControlState(openEventchanStellar.Control)
+// Version returns the version of the ForceClosedEvent.
+func (e *ForceClosedEvent) Version() Version {
+	return e.versionV
+}
 
-			err = checkOpen(controlsOpen)
-			if err != nil {
-				log.Println(err)
-			}
+// Timeout returns the timeout of the ForceClosedEvent.
+func (e *ForceClosedEvent) Timeout() pchannel.Timeout {
+	return e.timeout
+}
 
-			openEvent := OpenEvent{
-				channel: openEventchanStellar,
-			}
+// SetID sets the ID of the ForceClosedEvent.
+func (e *ForceClosedEvent) SetID(id pchannel.ID) {
+	e.idv = id
+}
 
-			evs = append(evs, &openEvent)
+// GetChannel returns the channel of the WithdrawingEvent.
+func (e *WithdrawingEvent) GetChannel() wire.Channel {
+	return e.channel
+}
 
-		case EventTypeFundChannel:
-			fundEventchanStellar, _, err := GetChannelBoolFromEvents(ev.Body.V0.Data)
-			if err != nil {
-				return nil, err
-			}
-			pState, err := wire.ToState(fundEventchanStellar.State)
-			if err != nil {
-				return nil, err
-			}
-			fundEvent := FundEvent{
-				channel: fundEventchanStellar,
-				idv:     pState.ID,
-			}
-			log.Println("Funding Event received")
-			evs = append(evs, &fundEvent)
-		case EventTypeClosed:
-			closedEventchanStellar, err := GetChannelFromEvents(ev.Body.V0.Data)
-			if err != nil {
-				return nil, err
-			}
-			pState, err := wire.ToState(closedEventchanStellar.State)
-			if err != nil {
-				return nil, err
-			}
-			closeEvent := CloseEvent{
-				channel: closedEventchanStellar,
-				idv:     pState.ID,
-			}
-			log.Println("Close Event received")
-			evs = append(evs, &closeEvent)
-		case EventTypeWithdrawn:
-			withdrawnEventchanStellar, err := GetChannelFromEvents(ev.Body.V0.Data)
-			if err != nil {
-				return nil, err
-			}
-			pState, err := wire.ToState(withdrawnEventchanStellar.State)
-			if err != nil {
-				return nil, err
-			}
-			log.Println("Withdrawn Event received")
-			withdrawnEvent := WithdrawnEvent{
-				channel: withdrawnEventchanStellar,
-				idv:     pState.ID,
-			}
-			evs = append(evs, &withdrawnEvent)
+// GetType returns the type of the WithdrawingEvent.
+func (e *WithdrawingEvent) GetType() (EventType, error) {
+	return EventTypeWithdrawing, nil
+}
 
-		case EventTypeDisputed:
-			disputedEventchanStellar, err := GetChannelFromEvents(ev.Body.V0.Data)
-			if err != nil {
-				return nil, err
-			}
-			_, err = wire.ToState(disputedEventchanStellar.State)
-			if err != nil {
-				return nil, err
-			}
-			disputedEvent := DisputedEvent{
-				channel: disputedEventchanStellar,
+// ID returns the ID of the WithdrawingEvent.
+func (e *WithdrawingEvent) ID() pchannel.ID {
+	return e.idv
+}
+
+// Version returns the version of the WithdrawingEvent.
+func (e *WithdrawingEvent) Version() Version {
+	return e.versionV
+}
+
+// Timeout returns the timeout of the WithdrawingEvent.
+func (e *WithdrawingEvent) Timeout() pchannel.Timeout {
+	return e.timeout
+}
+
+// SetID sets the ID of the WithdrawingEvent.
+func (e *WithdrawingEvent) SetID(id pchannel.ID) {
+	e.idv = id
+}
+
+// DecodeEventsPerun decodes the events of the Perun contract at perunAddr from
+// a Stellar transaction meta data. It returns ErrNoSorobanMeta if the meta
+// data carries no Soroban events.
+func DecodeEventsPerun(txMeta xdr.TransactionMeta, perunAddr xdr.ScAddress) ([]PerunEvent, error) {
+	v3, ok := txMeta.GetV3()
+	if !ok || v3.SorobanMeta == nil {
+		return nil, ErrNoSorobanMeta
+	}
+	return DecodeContractEvents(v3.SorobanMeta.Events, perunAddr)
+}
+
+// DecodeContractEvents decodes the events emitted by the Perun contract at
+// perunAddr. The ID, version and timeout of each event are taken from the
+// channel it carries, see NewPerunEvent. Events of other contracts, like token transfers, are
+// skipped, unless they claim to be Perun events, in which case
+// ErrEventIntegrity is returned. Perun events of unsupported functions are
+// skipped.
+func DecodeContractEvents(txEvents []xdr.ContractEvent, perunAddr xdr.ScAddress) ([]PerunEvent, error) {
+	perunID, ok := perunAddr.GetContractId()
+	if !ok {
+		return nil, errors.New("perun address is not a contract address")
+	}
+	evs := make([]PerunEvent, 0)
+
+	for _, ev := range txEvents {
+		fn, isPerun, err := perunFunction(ev)
+		if err != nil {
+			return nil, err
+		}
+		if !isPerun {
+			continue
+		}
+		if ev.ContractId == nil || *ev.ContractId != perunID {
+			return nil, ErrEventIntegrity
+		}
+
+		eventType, found := STELLAR_PERUN_CHANNEL_CONTRACT_TOPICS[fn]
+		if !found {
+			// Newer versions of the contract may emit further events.
+			log.Printf("Skipping unsupported Perun event %s", fn)
+			continue
+		}
+
+		var chanStellar wire.Channel
+		switch eventType {
+		case EventTypeFundChannel, EventTypeWithdrawing:
+			chanStellar, _, err = GetChannelBoolFromEvents(ev.Body.V0.Data)
+		default:
+			chanStellar, err = GetChannelFromEvents(ev.Body.V0.Data)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if eventType == EventTypeOpen {
+			if err := checkOpen(initControlState(chanStellar.Control)); err != nil {
+				log.Println(err)
 			}
-			log.Println("Disputed Event received")
-			evs = append(evs, &disputedEvent)
 		}
+		log.Printf("Perun event %s received", fn)
+		perunEvent, err := NewPerunEvent(eventType, chanStellar)
+		if err != nil {
+			return nil, err
+		}
+		evs = append(evs, perunEvent)
 	}
 	return evs, nil
 }
 
+// NewPerunEvent creates the event of the given type carrying the channel. The
+// ID and version of the event are those of the channel's state, its timeout
+// is the timeout of the channel's dispute, see ChannelTimeout.
+func NewPerunEvent(eventType EventType, ch wire.Channel) (PerunEvent, error) {
+	pState, err := wire.ToState(ch.State)
+	if err != nil {
+		return nil, err
+	}
+	id, version, timeout := pState.ID, pState.Version, ChannelTimeout(ch)
+
+	switch eventType {
+	case EventTypeOpen:
+		return &OpenEvent{channel: ch, idv: id, versionV: version, timeout: timeout}, nil
+	case EventTypeFundChannel:
+		return &FundEvent{channel: ch, idv: id, versionV: version, timeout: timeout}, nil
+	case EventTypeFundedChannel:
+		return &FundedEvent{channel: ch, idv: id, versionV: version, timeout: timeout}, nil
+	case EventTypeClosed:
+		return &CloseEvent{channel: ch, idv: id, versionV: version, timeout: timeout}, nil
+	case EventTypeWithdrawing:
+		return &WithdrawingEvent{channel: ch, idv: id, versionV: version, timeout: timeout}, nil
+	case EventTypeWithdrawn:
+		return &WithdrawnEvent{channel: ch, idv: id, versionV: version, timeout: timeout}, nil
+	case EventTypeForceClose:
+		return &ForceClosedEvent{channel: ch, idv: id, versionV: version, timeout: timeout}, nil
+	case EventTypeDisputed:
+		return &DisputedEvent{channel: ch, idv: id, versionV: version, timeout: timeout}, nil
+	default:
+		return nil, ErrEventUnsupported
+	}
+}
+
+// perunFunction returns the function symbol of an event emitted by the Perun
+// contract, whose first topic is the Perun symbol. It reports false for other
+// events.
+func perunFunction(ev xdr.ContractEvent) (xdr.ScSymbol, bool, error) {
+	if ev.Body.V0 == nil {
+		return "", false, nil
+	}
+	topics := ev.Body.V0.Topics
+	if len(topics) == 0 {
+		return "", false, nil
+	}
+	if perunString, ok := topics[0].GetSym(); !ok || perunString != AssertPerunSymbol {
+		return "", false, nil
+	}
+	if len(topics) < 2 { //nolint:gomnd
+		return "", true, ErrNotStellarPerunContract
+	}
+	fn, ok := topics[1].GetSym()
+	if !ok {
+		return "", true, ErrNotStellarPerunContract
+	}
+	return fn, true, nil
+}
+
 func initControlState(control wire.Control) controlsState {
 	return controlsState{
 		"ControlFundedA":    control.FundedA,
@@ -450,11 +565,14 @@ func GetChannelBoolFromEvents(evData xdr.ScVal) (wire.Channel, bool, error) {
 	var chanStellar wire.Channel
 
 	mvec, ok := evData.GetVec()
-	if !ok {
+	if !ok || mvec == nil {
 		return wire.Channel{}, false, errors.New("expected vec")
 	}
 
 	vecVals := *mvec
+	if len(vecVals) != 2 { //nolint:gomnd
+		return wire.Channel{}, false, errors.New("expected vec of channel and bool")
+	}
 	eventBool := vecVals[1]
 	eventControl := vecVals[0]
 	err := chanStellar.FromScVal(eventControl)
@@ -541,7 +659,8 @@ func AssertCloseEvent(perunEvents []PerunEvent) error {
 	return nil
 }
 
-// AssertWithdrawEvent asserts that a withdraw event is present in the list of events.
+// AssertWithdrawEvent asserts that a withdraw event is present in the list of
+// events. It reports whether both parties have withdrawn.
 func AssertWithdrawEvent(perunEvents []PerunEvent) (bool, error) {
 	for _, ev := range perunEvents {
 		eventType, err := ev.GetType()
@@ -550,7 +669,8 @@ func AssertWithdrawEvent(perunEvents []PerunEvent) (bool, error) {
 		}
 		switch eventType {
 		case EventTypeWithdrawing:
-			return false, nil
+			// One party withdrew, both did only if the withdrawn event
+			// follows.
 		case EventTypeWithdrawn:
 			return true, nil
 		default:
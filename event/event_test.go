@@ -0,0 +1,163 @@
+// Copyright 2025 PolyCrypt GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event_test
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/require"
+	pchannel "perun.network/go-perun/channel"
+
+	chtest "perun.network/perun-stellar-backend/channel/test"
+	"perun.network/perun-stellar-backend/event"
+)
+
+func contractEvent(contractID xdr.Hash, data xdr.ScVal, topics ...string) xdr.ContractEvent {
+	vals := make(xdr.ScVec, len(topics))
+	for i, topic := range topics {
+		sym := xdr.ScSymbol(topic)
+		vals[i] = xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+	}
+	return xdr.ContractEvent{
+		ContractId: &contractID,
+		Type:       xdr.ContractEventTypeContract,
+		Body:       xdr.ContractEventBody{V: 0, V0: &xdr.ContractEventV0{Topics: vals, Data: data}},
+	}
+}
+
+func TestDecodeContractEvents(t *testing.T) {
+	setup := chtest.NewSimSetup(t, false)
+	params, state := setup.NewParamsAndState(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second) //nolint:gomnd
+	defer cancel()
+	inv := setup.Contract.Invoker(setup.Addrs[0])
+	cursor, err := inv.LatestEventCursor(ctx)
+	require.NoError(t, err)
+
+	// Run through the whole lifecycle of a channel, so that every event is
+	// emitted.
+	require.NoError(t, setup.Fund(ctx, params, state))
+	next := state.Clone()
+	next.Version = 3
+	next.Balances[0][0] = new(big.Int).Sub(next.Balances[0][0], big.NewInt(10)) //nolint:gomnd
+	next.Balances[0][1] = new(big.Int).Add(next.Balances[0][1], big.NewInt(10)) //nolint:gomnd
+	sigs := setup.Sign(t, next)
+	req := pchannel.AdjudicatorReq{Params: params, Tx: pchannel.Transaction{State: next, Sigs: sigs}, Idx: 1}
+	require.NoError(t, setup.Adjs[1].Register(ctx, req, nil))
+	disputed, err := setup.Channel(state.ID)
+	require.NoError(t, err)
+	setup.Contract.AdvanceTime(chtest.SimChallengeDuration * time.Second)
+	for i, adj := range setup.Adjs {
+		req.Idx = pchannel.Index(i)
+		require.NoError(t, adj.Withdraw(ctx, req, nil))
+	}
+
+	raw, _, err := inv.PerunEvents(ctx, setup.Contract.Address(), cursor)
+	require.NoError(t, err)
+	// Events of other contracts in the same transaction are skipped.
+	foreign := contractEvent(xdr.Hash{1}, xdr.ScVal{Type: xdr.ScValTypeScvVoid}, "mint")
+	raw = append([]xdr.ContractEvent{foreign}, raw...)
+
+	evs, err := event.DecodeContractEvents(raw, setup.Contract.Address())
+	require.NoError(t, err)
+	want := []event.PerunEvent{
+		&event.OpenEvent{}, &event.FundEvent{}, &event.FundEvent{}, &event.FundedEvent{},
+		&event.DisputedEvent{}, &event.ForceClosedEvent{},
+		&event.WithdrawingEvent{}, &event.WithdrawingEvent{}, &event.WithdrawnEvent{},
+	}
+	require.Len(t, evs, len(want))
+	for i, ev := range evs {
+		require.Equal(t, reflect.TypeOf(want[i]), reflect.TypeOf(ev), "event %d", i)
+		require.Equal(t, state.ID, ev.ID(), "event %d", i)
+	}
+
+	require.Zero(t, evs[0].Version())
+	require.True(t, evs[0].Timeout().IsElapsed(ctx), "the timeout of undisputed channels is elapsed")
+	dispute := evs[4]
+	require.Equal(t, next.Version, dispute.Version())
+	expiry := int64(disputed.Control.Timestamp) + chtest.SimChallengeDuration
+	require.Equal(t, &pchannel.TimeTimeout{Time: time.Unix(expiry, 0)}, dispute.Timeout())
+	withdrawn, err := event.AssertWithdrawEvent(evs[6:])
+	require.NoError(t, err)
+	require.True(t, withdrawn)
+	// The withdrawal of one party does not finish the channel.
+	withdrawn, err = event.AssertWithdrawEvent(evs[6:7])
+	require.NoError(t, err)
+	require.False(t, withdrawn)
+}
+
+func TestDecodeContractEventsErrors(t *testing.T) {
+	void := xdr.ScVal{Type: xdr.ScValTypeScvVoid}
+	perunID := xdr.Hash{1}
+	perunAddr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &perunID}
+	decode := func(ev xdr.ContractEvent) error {
+		_, err := event.DecodeContractEvents([]xdr.ContractEvent{ev}, perunAddr)
+		return err
+	}
+
+	// Events of unsupported functions are skipped.
+	evs, err := event.DecodeContractEvents([]xdr.ContractEvent{contractEvent(perunID, void, "perun", "unknown")}, perunAddr)
+	require.NoError(t, err)
+	require.Empty(t, evs)
+	require.ErrorIs(t, decode(contractEvent(xdr.Hash{2}, void, "perun", "unknown")), event.ErrEventIntegrity)
+	require.ErrorIs(t, decode(contractEvent(perunID, void, "perun")), event.ErrNotStellarPerunContract)
+	require.Error(t, decode(contractEvent(perunID, void, "perun", "dispute")))
+}
+
+func TestDecodeEventsPerunWithoutSorobanMeta(t *testing.T) {
+	perunID := xdr.Hash{1}
+	perunAddr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &perunID}
+
+	for _, meta := range []xdr.TransactionMeta{
+		{V: 2, V2: &xdr.TransactionMetaV2{}},
+		{V: 3, V3: &xdr.TransactionMetaV3{}},
+	} {
+		_, err := event.DecodeEventsPerun(meta, perunAddr)
+		require.ErrorIs(t, err, event.ErrNoSorobanMeta, "meta v%d", meta.V)
+	}
+}
+
+func TestDecodeContractEventsIntegrity(t *testing.T) {
+	setup := chtest.NewSimSetup(t, false)
+	params, state := setup.NewParamsAndState(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second) //nolint:gomnd
+	defer cancel()
+	inv := setup.Contract.Invoker(setup.Addrs[0])
+	cursor, err := inv.LatestEventCursor(ctx)
+	require.NoError(t, err)
+	require.NoError(t, setup.Fund(ctx, params, state))
+	raw, _, err := inv.PerunEvents(ctx, setup.Contract.Address(), cursor)
+	require.NoError(t, err)
+	require.NotEmpty(t, raw)
+
+	// A contract invoked in the same transaction forges a Perun event.
+	forged := raw[len(raw)-1]
+	forged.ContractId = &xdr.Hash{1}
+	_, err = event.DecodeContractEvents(append(raw, forged), setup.Contract.Address())
+	require.ErrorIs(t, err, event.ErrEventIntegrity)
+	forged.ContractId = nil
+	_, err = event.DecodeContractEvents(append(raw, forged), setup.Contract.Address())
+	require.ErrorIs(t, err, event.ErrEventIntegrity)
+
+	otherID := xdr.Hash{2}
+	other := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &otherID}
+	_, err = event.DecodeContractEvents(raw, other)
+	require.ErrorIs(t, err, event.ErrEventIntegrity)
+}
@@ -18,6 +18,8 @@ import (
 	"time"
 
 	pchannel "perun.network/go-perun/channel"
+
+	"perun.network/perun-stellar-backend/wire"
 )
 
 // NewTimeTimeout returns a new Timeout which expires at the given time.
@@ -30,3 +32,14 @@ func MakeTimeout(challDur time.Duration) pchannel.Timeout {
 	expirationTime := time.Now().Add(challDur)
 	return NewTimeTimeout(expirationTime)
 }
+
+// ChannelTimeout returns the timeout of the dispute of the channel, which
+// expires the challenge duration after the dispute was registered. The
+// timeout of an undisputed channel is elapsed.
+func ChannelTimeout(ch wire.Channel) pchannel.Timeout {
+	if !ch.Control.Disputed {
+		return &pchannel.ElapsedTimeout{}
+	}
+	expiry := uint64(ch.Control.Timestamp) + uint64(ch.Params.ChallengeDuration)
+	return NewTimeTimeout(time.Unix(int64(expiry), 0))
+}
@@ -144,6 +144,11 @@ func MustWrapScBytes(b xdr.ScBytes) (xdr.ScVal, error) {
 	return v, nil
 }
 
+// WrapUint32 wraps a Uint32 into a xdr.ScVal.
+func WrapUint32(i xdr.Uint32) (xdr.ScVal, error) {
+	return xdr.NewScVal(xdr.ScValTypeScvU32, i)
+}
+
 // WrapUint64 wraps a Uint64 into a xdr.ScVal.
 func WrapUint64(i xdr.Uint64) (xdr.ScVal, error) {
 	return xdr.NewScVal(xdr.ScValTypeScvU64, i)